@@ -0,0 +1,332 @@
+package hyperliquid
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// The L1 signature is a hash of the msgpack encoding of the action (see buildActionHash).
+// The exchange rebuilds it from the JSON it receives, like the official Python SDK does with its dicts:
+// maps keep the order of their keys, integers use their smallest format and strings are never binary.
+// An encoding that differs by one byte gives a valid signature of another address, which the exchange
+// reports as an unknown user or missing agent.
+// https://github.com/hyperliquid-dex/hyperliquid-python-sdk/blob/master/hyperliquid/utils/signing.py
+
+// orderedMap is a msgpack map that keeps the order of its keys.
+type orderedMap []orderedField
+
+type orderedField struct {
+	key   string
+	value any
+}
+
+// canonicalAction is an action with an explicit msgpack encoding.
+// The fields are in the order of the Python SDK, so the encoding doesn't depend on struct tags.
+type canonicalAction interface {
+	msgpackFields() orderedMap
+}
+
+// EncodeAction returns the canonical msgpack encoding of an L1 action, which is hashed for its signature.
+//
+// The actions of this package have an explicit key order. Raw JSON (json.RawMessage) keeps the order of its keys.
+// Other actions are encoded through their JSON, so their keys are in the order of their struct fields.
+// Integers use the smallest msgpack format, floats are float64 and strings are msgpack strings.
+func EncodeAction(action any) ([]byte, error) {
+	value, err := canonicalValue(action)
+	if err != nil {
+		return nil, err
+	}
+	return encodeOrdered(value)
+}
+
+// canonicalValue returns the action as the values encoded by encodeOrderedValue
+func canonicalValue(action any) (any, error) {
+	switch a := action.(type) {
+	case json.RawMessage:
+		return decodeJSON(a)
+	case canonicalAction:
+		return a.msgpackFields(), nil
+	case MultiSigAction:
+		return a.canonicalFields()
+	}
+	data, err := json.Marshal(action)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSON(data)
+}
+
+func (action PlaceOrderAction) msgpackFields() orderedMap {
+	orders := make([]any, len(action.Orders))
+	for i, order := range action.Orders {
+		orders[i] = order.msgpackFields()
+	}
+	return orderedMap{
+		{"type", action.Type},
+		{"orders", orders},
+		{"grouping", string(action.Grouping)},
+	}
+}
+
+func (order OrderWire) msgpackFields() orderedMap {
+	fields := orderedMap{
+		{"a", order.Asset},
+		{"b", order.IsBuy},
+		{"p", order.LimitPx},
+		{"s", order.SizePx},
+		{"r", order.ReduceOnly},
+		{"t", order.OrderType.msgpackFields()},
+	}
+	// The client order id is only sent if it's set
+	if order.Cloid != "" {
+		fields = append(fields, orderedField{"c", order.Cloid})
+	}
+	return fields
+}
+
+func (orderType OrderTypeWire) msgpackFields() orderedMap {
+	var fields orderedMap
+	if orderType.Limit != nil {
+		fields = append(fields, orderedField{"limit", orderedMap{{"tif", orderType.Limit.Tif}}})
+	}
+	if orderType.Trigger != nil {
+		fields = append(fields, orderedField{"trigger", orderedMap{
+			{"isMarket", orderType.Trigger.IsMarket},
+			{"triggerPx", orderType.Trigger.TriggerPx},
+			{"tpsl", string(orderType.Trigger.TpSl)},
+		}})
+	}
+	return fields
+}
+
+func (action ModifyOrderAction) msgpackFields() orderedMap {
+	modifies := make([]any, len(action.Modifies))
+	for i, modify := range action.Modifies {
+		modifies[i] = orderedMap{
+			{"oid", modify.OrderId},
+			{"order", modify.Order.msgpackFields()},
+		}
+	}
+	return orderedMap{
+		{"type", action.Type},
+		{"modifies", modifies},
+	}
+}
+
+func (action CancelOidOrderAction) msgpackFields() orderedMap {
+	cancels := make([]any, len(action.Cancels))
+	for i, cancel := range action.Cancels {
+		cancels[i] = orderedMap{
+			{"a", cancel.Asset},
+			{"o", cancel.Oid},
+		}
+	}
+	return orderedMap{
+		{"type", action.Type},
+		{"cancels", cancels},
+	}
+}
+
+func (action CancelCloidOrderAction) msgpackFields() orderedMap {
+	cancels := make([]any, len(action.Cancels))
+	for i, cancel := range action.Cancels {
+		cancels[i] = orderedMap{
+			{"asset", cancel.Asset},
+			{"cloid", cancel.Cloid},
+		}
+	}
+	return orderedMap{
+		{"type", action.Type},
+		{"cancels", cancels},
+	}
+}
+
+func (action UpdateLeverageAction) msgpackFields() orderedMap {
+	return orderedMap{
+		{"type", action.Type},
+		{"asset", action.Asset},
+		{"isCross", action.IsCross},
+		{"leverage", action.Leverage},
+	}
+}
+
+// The inner action is encoded like any other action
+func (action MultiSigAction) canonicalFields() (orderedMap, error) {
+	signatures := make([]any, len(action.Signatures))
+	for i, signature := range action.Signatures {
+		signatures[i] = orderedMap{
+			{"r", signature.R},
+			{"s", signature.S},
+			{"v", int(signature.V)},
+		}
+	}
+	inner, err := canonicalValue(action.Payload.Action)
+	if err != nil {
+		return nil, err
+	}
+	return orderedMap{
+		{"type", action.Type},
+		{"signatureChainId", action.SignatureChainID},
+		{"signatures", signatures},
+		{"payload", orderedMap{
+			{"multiSigUser", action.Payload.MultiSigUser},
+			{"outerSigner", action.Payload.OuterSigner},
+			{"action", inner},
+		}},
+	}, nil
+}
+
+// multiSigActionHash returns the hash of a multiSig action signed by the outer signer.
+// Unlike L1 actions, the type of the action is not part of the hash.
+func multiSigActionHash(action any, vaultAddress string, nonce uint64) (common.Hash, error) {
+	value, err := canonicalValue(action)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	fields, ok := value.(orderedMap)
+	if !ok {
+		return common.Hash{}, APIError{Message: "multiSig action must be an object"}
+	}
+	withoutType := orderedMap{}
+	for _, field := range fields {
+		if field.key != "type" {
+			withoutType = append(withoutType, field)
+		}
+	}
+	data, err := encodeOrdered(withoutType)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return buildActionHashFromMsgpack(data, vaultAddress, nonce), nil
+}
+
+func encodeOrdered(value any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeOrderedValue(msgpack.NewEncoder(&buf), value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// jsonToMsgpack encodes a JSON document with msgpack, keeping the key order of the objects.
+func jsonToMsgpack(data []byte) ([]byte, error) {
+	value, err := decodeJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return encodeOrdered(value)
+}
+
+// decodeJSON decodes a JSON document with its objects as orderedMap and its numbers as json.Number
+func decodeJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	value, err := decodeJSONValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+	return value, nil
+}
+
+// An object or array is decoded completely before it is encoded, because msgpack needs its length first.
+func decodeJSONValue(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('{'):
+		fields := orderedMap{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, orderedField{key.(string), value})
+		}
+		_, err = decoder.Token() // '}'
+		return fields, err
+	case json.Delim('['):
+		values := []any{}
+		for decoder.More() {
+			value, err := decodeJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		_, err = decoder.Token() // ']'
+		return values, err
+	}
+	return token, nil
+}
+
+func encodeOrderedValue(encoder *msgpack.Encoder, value any) error {
+	switch v := value.(type) {
+	case orderedMap:
+		if err := encoder.EncodeMapLen(len(v)); err != nil {
+			return err
+		}
+		for _, field := range v {
+			if err := encoder.EncodeString(field.key); err != nil {
+				return err
+			}
+			if err := encodeOrderedValue(encoder, field.value); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []any:
+		if err := encoder.EncodeArrayLen(len(v)); err != nil {
+			return err
+		}
+		for _, item := range v {
+			if err := encodeOrderedValue(encoder, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case json.Number:
+		if strings.ContainsAny(v.String(), ".eE") {
+			f, err := v.Float64()
+			if err != nil {
+				return err
+			}
+			return encoder.EncodeFloat64(f)
+		}
+		if i, err := v.Int64(); err == nil {
+			return encoder.EncodeInt(i)
+		}
+		var u uint64
+		if _, err := fmt.Sscan(v.String(), &u); err != nil {
+			return fmt.Errorf("invalid number %s", v)
+		}
+		return encoder.EncodeUint(u)
+	case int:
+		return encoder.EncodeInt(int64(v))
+	case int64:
+		return encoder.EncodeInt(v)
+	case uint64:
+		return encoder.EncodeUint(v)
+	case string:
+		return encoder.EncodeString(v)
+	case bool:
+		return encoder.EncodeBool(v)
+	case nil:
+		return encoder.EncodeNil()
+	}
+	return fmt.Errorf("unexpected value %v (%T)", value, value)
+}
@@ -0,0 +1,263 @@
+package hyperliquid
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Signatures of the official Python SDK tests (tests/signing_test.py) with the same key.
+// The Python SDK strips the leading zeros of r and s.
+func TestEncodeAction_GoldenVectors(t *testing.T) {
+	limit := OrderTypeWire{Limit: &LimitOrderType{Tif: TifGtc}}
+	trigger := OrderTypeWire{Trigger: &TriggerOrderType{IsMarket: true, TriggerPx: "103", TpSl: TriggerSl}}
+	vault := "0x1719884eb866cb12b2287399b15f7db5e7d775ea"
+	type signature struct {
+		r, s string
+		v    byte
+	}
+	testCases := []struct {
+		name         string
+		action       any
+		vaultAddress *string
+		connectionId string
+		mainnet      signature
+		testnet      signature
+	}{
+		{
+			name:         "dummy",
+			action:       json.RawMessage(`{"type":"dummy","num":100000000000}`),
+			connectionId: "0xf528daee6a0bd11407b483cfcd9a48c56884180b70ee86f124053e5fc1bf4d57",
+			mainnet:      signature{"053749d5b30552aeb2fca34b530185976545bb22d0b3ce6f62e31be961a59298", "755c40ba9bf05223521753995abb2f73ab3229be8ec921f350cb447e384d8ed8", 27},
+			testnet:      signature{"542af61ef1f429707e3c76c5293c80d01f74ef853e34b76efffcb57e574f9510", "17b8b32f086e8cdede991f1e2c529f5dd5297cbe8128500e00cbaf766204a613", 28},
+		},
+		{
+			name:         "dummy with vault",
+			action:       json.RawMessage(`{"type":"dummy","num":100000000000}`),
+			vaultAddress: &vault,
+			connectionId: "0xde9e09a7a3da45cc694096d4bfdcd89bc1b892d05497c5ecc1f56c335945184c",
+			mainnet:      signature{"003c548db75e479f8012acf3000ca3a6b05606bc2ec0c29c50c515066a326239", "4d402be7396ce74fbba3795769cda45aec00dc3125a984f2a9f23177b190da2c", 28},
+			testnet:      signature{"e281d2fb5c6e25ca01601f878e4d69c965bb598b88fac58e475dd1f5e56c362b", "7ddad27e9a238d045c035bc606349d075d5c5cd00a6cd1da23ab5c39d4ef0f60", 27},
+		},
+		{
+			name:         "limit order",
+			action:       OrderWiresToOrderAction([]OrderWire{{Asset: 1, IsBuy: true, LimitPx: "100", SizePx: "100", OrderType: limit}}, GroupingNa),
+			connectionId: "0x884f2c32bb6dbdd65f6033e32fb28c0cb6f5b345db0f6471fd3366d85c9252c1",
+			mainnet:      signature{"d65369825a9df5d80099e513cce430311d7d26ddf477f5b3a33d2806b100d78e", "2b54116ff64054968aa237c20ca9ff68000f977c93289157748a3162b6ea940e", 28},
+			testnet:      signature{"82b2ba28e76b3d761093aaded1b1cdad4960b3af30212b343fb2e6cdfa4e3d54", "6b53878fc99d26047f4d7e8c90eb98955a109f44209163f52d8dc4278cbbd9f5", 27},
+		},
+		{
+			name:         "limit order with cloid",
+			action:       OrderWiresToOrderAction([]OrderWire{{Asset: 1, IsBuy: true, LimitPx: "100", SizePx: "100", OrderType: limit, Cloid: "0x00000000000000000000000000000001"}}, GroupingNa),
+			connectionId: "0x0ba500cedd8f4ba6ded620a0b1cd04f124d9ba745e2e2893fcc763bcc1444af5",
+			mainnet:      signature{"041ae18e8239a56cacbc5dad94d45d0b747e5da11ad564077fcac71277a946e3", "3c61f667e747404fe7eea8f90ab0e76cc12ce60270438b2058324681a00116da", 27},
+			testnet:      signature{"eba0664bed2676fc4e5a743bf89e5c7501aa6d870bdb9446e122c9466c5cd16d", "7f3e74825c9114bc59086f1eebea2928c190fdfbfde144827cb02b85bbe90988", 28},
+		},
+		{
+			name:         "trigger order",
+			action:       OrderWiresToOrderAction([]OrderWire{{Asset: 1, IsBuy: true, LimitPx: "100", SizePx: "100", OrderType: trigger}}, GroupingNa),
+			connectionId: "0x430a86fb9876e901920d931f5bb20c9d011f6389bd179f39a73c09e6219adcad",
+			mainnet:      signature{"98343f2b5ae8e26bb2587daad3863bc70d8792b09af1841b6fdd530a2065a3f9", "6b5bb6bb0633b710aa22b721dd9dee6d083646a5f8e581a20b545be6c1feb405", 27},
+			testnet:      signature{"971c554d917c44e0e1b6cc45d8f9404f32172a9d3b3566262347d0302896a2e4", "206257b104788f80450f8e786c329daa589aa0b32ba96948201ae556d5637eac", 28},
+		},
+	}
+	km, _ := NewPKeyManager(testPrivateKey)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := ExchangeRequest{Action: tc.action, Nonce: 0, VaultAddress: tc.vaultAddress}
+			for _, isMainnet := range []bool{true, false} {
+				want := tc.testnet
+				if isMainnet {
+					want = tc.mainnet
+				}
+				signRequest, err := buildRequestSignRequest(request, isMainnet)
+				if err != nil {
+					t.Fatalf("buildRequestSignRequest() error = %v", err)
+				}
+				if got := fmt.Sprint(signRequest.DTypeMsg["connectionId"]); got != tc.connectionId {
+					t.Errorf("connectionId = %s, want %s", got, tc.connectionId)
+				}
+				v, r, s, err := SignTypedData(km, signRequest)
+				if err != nil {
+					t.Fatalf("SignTypedData() error = %v", err)
+				}
+				got := signature{hex.EncodeToString(r[:]), hex.EncodeToString(s[:]), v}
+				if got != want {
+					t.Errorf("signature (mainnet %v) = %+v, want %+v", isMainnet, got, want)
+				}
+			}
+		})
+	}
+}
+
+// Signatures of the user signed actions of the Python SDK tests on testnet
+func TestEncodeAction_UserSignedGoldenVectors(t *testing.T) {
+	testCases := []struct {
+		action UserSignedAction
+		r, s   string
+		v      byte
+	}{
+		{
+			action: UsdSendAction{Type: "usdSend", Destination: "0x5e9ee1089755c3435139848e47e6635505d5a13a", Amount: "1"},
+			r:      "637b37dd731507cdd24f46532ca8ba6eec616952c56218baeff04144e4a77073",
+			s:      "11a6a24900e6e314136d2592e2f8d502cd89b7c15b198e1bee043c9589f9fad7",
+			v:      27,
+		},
+		{
+			action: WithdrawAction{Type: "withdraw3", Destination: "0x5e9ee1089755c3435139848e47e6635505d5a13a", Amount: "1"},
+			r:      "8363524c799e90ce9bc41022f7c39b4e9bdba786e5f9c72b20e43e1462c37cf9",
+			s:      "58b1411a775938b83e29182e8ef74975f9054c8e97ebf5ec2dc8d51bfc893881",
+			v:      28,
+		},
+	}
+	km, _ := NewPKeyManager(testPrivateKey)
+	for _, tc := range testCases {
+		t.Run(tc.action.ActionType(), func(t *testing.T) {
+			nonce := uint64(1687816341423)
+			action := tc.action.WithSignatureParams(nonce, "0x66eee", "Testnet")
+			signRequest, err := buildRequestSignRequest(ExchangeRequest{Action: action, Nonce: nonce}, false)
+			if err != nil {
+				t.Fatalf("buildRequestSignRequest() error = %v", err)
+			}
+			v, r, s, err := SignTypedData(km, signRequest)
+			if err != nil {
+				t.Fatalf("SignTypedData() error = %v", err)
+			}
+			if hex.EncodeToString(r[:]) != tc.r || hex.EncodeToString(s[:]) != tc.s || v != tc.v {
+				t.Errorf("signature = %x %x %d, want %s %s %d", r, s, v, tc.r, tc.s, tc.v)
+			}
+		})
+	}
+}
+
+// Signatures of the multi-sig functions of the Python SDK (sign_multi_sig_l1_action_payload,
+// sign_multi_sig_user_signed_action_payload and sign_multi_sig_action) on testnet with the same key,
+// the multi-sig user 0x00000000000000000000000000000000000000aa and the nonce 1700000000000.
+// They were computed with an offline port of these functions which reproduces the vectors above.
+// The signatures are the SDK's, without the leading zeros of r and s.
+func TestEncodeAction_MultiSigGoldenVectors(t *testing.T) {
+	testCases := []struct {
+		action             Action
+		payload            RsvSignature // Signature of the authorized user
+		multiSigActionHash string
+		multiSig           RsvSignature // Signature of the outer signer
+	}{
+		{
+			action: UpdateLeverageAction{Type: "updateLeverage", Asset: 1, IsCross: true, Leverage: 5},
+			payload: RsvSignature{
+				R: "0xe91b8ecbd6a4a54895e4e9c86f3136eb9408388bbadd041806dc16bf81b19a00",
+				S: "0x75811e7276197f4a955486e5505a3f21500ab25ce174801c7c129f394d59a23a",
+				V: 27,
+			},
+			multiSigActionHash: "0x5e355a1d6f743ad41cc37228efea37953fc4085fbcfd7ac6b230c4f6da72446c",
+			multiSig: RsvSignature{
+				R: "0x5e5e2ad4987e1089bb59c1ed72a12b261be0adec468525f2f7995d48224ee8d",
+				S: "0x19ef2b5949158724736b12bcebe8360a34dfda6585e5177291846738d4aed7ff",
+				V: 28,
+			},
+		},
+		{
+			action: usdSendAction("0x0000000000000000000000000000000000000002", 100),
+			payload: RsvSignature{
+				R: "0xa2e5cb98e68a9ce2093fe5dea89ac9c09c3526c2b3d9273d962e3c93d88a35d",
+				S: "0x7d73bf6df837f1b7e5706aed689c1a6171f48a0ba73808643e2f16179304cd46",
+				V: 27,
+			},
+			multiSigActionHash: "0x35a130e185294dae4a9b29166736db7e18be1093c509454ebd7206799fb73a17",
+			multiSig: RsvSignature{
+				R: "0x3a3b4b1dba8aacde394399596f849b9e0d930e40ee65683399d5e5d96e5952a7",
+				S: "0x39b286bfb3729e22a7009400b78f1552c67b93e301010eeaf4566dcfc1edc31b",
+				V: 28,
+			},
+		},
+	}
+	sameSignature := func(a, b RsvSignature) bool {
+		return common.HexToHash(a.R) == common.HexToHash(b.R) && common.HexToHash(a.S) == common.HexToHash(b.S) && a.V == b.V
+	}
+	now := time.UnixMilli(1700000000000)
+	km, _ := NewPKeyManager(testPrivateKey)
+	api := GetMockExchangeAPI(t, func(request ExchangeRequest) any { return nil })
+	api.SetClock(func() time.Time { return now })
+	for _, tc := range testCases {
+		t.Run(tc.action.ActionType(), func(t *testing.T) {
+			api.SetNonceManager(NewNonceManager(NewMemoryNonceStore()))
+			collector, err := api.NewMultiSigCollector("0x00000000000000000000000000000000000000AA", tc.action, time.Hour)
+			if err != nil {
+				t.Fatalf("NewMultiSigCollector() error = %v", err)
+			}
+			if collector.Nonce() != 1700000000000 {
+				t.Fatalf("Nonce() = %d, want 1700000000000", collector.Nonce())
+			}
+			envelope := collector.Envelope()
+			if err := envelope.Sign(km, now); err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+			if !sameSignature(*envelope.Signature, tc.payload) {
+				t.Errorf("payload signature = %+v, want %+v", *envelope.Signature, tc.payload)
+			}
+
+			// The SDK sends the signatures without the leading zeros, they are part of the hash
+			action := collector.Action()
+			action.Signatures = []RsvSignature{tc.payload}
+			hash, err := multiSigActionHash(action, "", collector.Nonce())
+			if err != nil {
+				t.Fatalf("multiSigActionHash() error = %v", err)
+			}
+			if hash.Hex() != tc.multiSigActionHash {
+				t.Errorf("multiSigActionHash() = %s, want %s", hash.Hex(), tc.multiSigActionHash)
+			}
+			v, r, s, err := SignTypedData(km, buildMultiSigSignRequest(hash, action.SignatureChainID, collector.Nonce(), false))
+			if err != nil {
+				t.Fatalf("SignTypedData() error = %v", err)
+			}
+			if got := ToTypedSig(r, s, v); !sameSignature(got, tc.multiSig) {
+				t.Errorf("multiSig signature = %+v, want %+v", got, tc.multiSig)
+			}
+		})
+	}
+}
+
+func TestEncodeAction_Bytes(t *testing.T) {
+	action := CancelOidOrderAction{Type: "cancel", Cancels: []CancelOidWire{{Asset: 1, Oid: 123}}}
+	data, err := EncodeAction(action)
+	if err != nil {
+		t.Fatalf("EncodeAction() error = %v", err)
+	}
+	// {"type":"cancel","cancels":[{"a":1,"o":123}]} with fixmap, fixstr, fixarray and positive fixint
+	want := "82a474797065a663616e63656ca763616e63656c739182a16101a16f7b"
+	if hex.EncodeToString(data) != want {
+		t.Errorf("EncodeAction() = %x, want %s", data, want)
+	}
+}
+
+// The exchange rebuilds the hash from the JSON of the action, so both encodings must be the same
+func TestEncodeAction_MatchesJSON(t *testing.T) {
+	limit := OrderTypeWire{Limit: &LimitOrderType{Tif: TifAlo}}
+	trigger := OrderTypeWire{Trigger: &TriggerOrderType{IsMarket: false, TriggerPx: "2400.5", TpSl: TriggerTp}}
+	order := OrderWire{Asset: 10001, IsBuy: false, LimitPx: "2400", SizePx: "0.1234", ReduceOnly: true, OrderType: trigger}
+	actions := []Action{
+		OrderWiresToOrderAction([]OrderWire{order, {Asset: 1, LimitPx: "1", SizePx: "300", OrderType: limit, Cloid: "0x00000000000000000000000000000002"}}, GroupingTpSl),
+		ModifyOrderAction{Type: "batchModify", Modifies: []ModifyOrderWire{{OrderId: 1 << 40, Order: order}}},
+		CancelOidOrderAction{Type: "cancel", Cancels: []CancelOidWire{{Asset: 300, Oid: 70000}}},
+		CancelCloidOrderAction{Type: "cancelByCloid", Cancels: []CancelCloidWire{{Asset: 0, Cloid: "0x00000000000000000000000000000003"}}},
+		UpdateLeverageAction{Type: "updateLeverage", Asset: 5, IsCross: false, Leverage: 50},
+	}
+	for _, action := range actions {
+		data, err := EncodeAction(action)
+		if err != nil {
+			t.Fatalf("EncodeAction(%s) error = %v", action.ActionType(), err)
+		}
+		raw, _ := json.Marshal(action)
+		fromJSON, err := EncodeAction(json.RawMessage(raw))
+		if err != nil {
+			t.Fatalf("EncodeAction(%s JSON) error = %v", action.ActionType(), err)
+		}
+		if hex.EncodeToString(data) != hex.EncodeToString(fromJSON) {
+			t.Errorf("EncodeAction(%s) = %x, from JSON %x", action.ActionType(), data, fromJSON)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Action is an action of the /exchange endpoint (the "action" field of ExchangeRequest).
+// Actions are signed by the agent key with the L1 scheme, unless they implement UserSignedAction.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/signing
+type Action interface {
+	// ActionType returns the "type" field of the action, e.g. "order"
+	ActionType() string
+}
+
+// UserSignedAction is an action signed by the user with EIP-712 typed data, e.g. withdrawals and transfers.
+// Its nonce and network are fields of the signed message. They can't be sent for a vault.
+type UserSignedAction interface {
+	Action
+	// EIP712Types returns the primary type and the fields of the signed message
+	EIP712Types() (string, []apitypes.Type)
+	// WithSignatureParams returns a copy of the action with its nonce and network fields set
+	WithSignatureParams(nonce uint64, signatureChainId string, hyperliquidChain string) UserSignedAction
+}
+
+// ActionWithResponse is an action that declares the response of the exchange.
+type ActionWithResponse[R any] interface {
+	Action
+	// NewResponse returns an empty response of the action
+	NewResponse() *R
+}
+
+// Execute signs the action (see SignAction) and sends it to the exchange.
+// The type of the response is declared by the action.
+//
+//	res, err := Execute(api, UpdateLeverageAction{Type: "updateLeverage", Asset: 1, IsCross: true, Leverage: 10})
+func Execute[R any, A ActionWithResponse[R]](api *ExchangeAPI, action A) (*R, error) {
+	request, err := api.SignAction(action)
+	if err != nil {
+		return nil, err
+	}
+	return MakeUniversalRequest[R](api, request)
+}
+
+// SignAction builds a signed exchange request for the action with the next nonce of the signer (see NonceManager).
+// L1 actions are signed by the agent for the vault address (see SetVaultAddress).
+// User signed actions get the nonce and network fields set before they are signed by the user.
+func (api *ExchangeAPI) SignAction(action Action) (*ExchangeRequest, error) {
+	unsigned, err := api.PrepareAction(action)
+	if err != nil {
+		return nil, err
+	}
+	v, r, s, err := api.Sign(unsigned.signRequest)
+	if err != nil {
+		api.debug("Error signing %s action: %s", action.ActionType(), err)
+		return nil, err
+	}
+	return unsigned.request(ToTypedSig(r, s, v)), nil
+}
+
+// UnsignedAction is an exchange action with its nonce, to be signed outside of the client,
+// e.g. by a browser or hardware wallet with eth_signTypedData_v4.
+//
+//	unsigned, _ := api.PrepareWithdraw(destination, 100)
+//	typedData, _ := unsigned.TypedDataJSON() // Signed by the wallet
+//	res, err := SubmitSigned[WithdrawResponse](api, unsigned, signature)
+type UnsignedAction struct {
+	Action       Action
+	Nonce        uint64
+	VaultAddress *string
+	signRequest  *SignRequest
+}
+
+// PrepareAction sets the nonce of the action (see SignAction) and builds its EIP-712 typed data.
+// The nonce is taken from the signer, or the account address if the client has no signer.
+func (api *ExchangeAPI) PrepareAction(action Action) (*UnsignedAction, error) {
+	nonce, err := api.nextNonce()
+	if err != nil {
+		return nil, err
+	}
+	if userSigned, ok := action.(UserSignedAction); ok {
+		signatureChainId, chain := api.getChainParams()
+		userSigned = userSigned.WithSignatureParams(nonce, signatureChainId, chain)
+		primaryType, types := userSigned.EIP712Types()
+		srequest, err := api.BuildUserSignedEIP712Message(userSigned, types, primaryType)
+		if err != nil {
+			api.debug("Error building EIP712 message: %s", err)
+			return nil, err
+		}
+		return &UnsignedAction{Action: userSigned, Nonce: nonce, VaultAddress: nil, signRequest: srequest}, nil
+	}
+	srequest, err := api.BuildEIP712Message(action, nonce)
+	if err != nil {
+		api.debug("Error building EIP712 message: %s", err)
+		return nil, err
+	}
+	return &UnsignedAction{Action: action, Nonce: nonce, VaultAddress: api.getVaultAddress(), signRequest: srequest}, nil
+}
+
+// TypedData returns the EIP-712 typed data to sign.
+func (unsigned *UnsignedAction) TypedData() apitypes.TypedData {
+	return SignRequestToEIP712TypedData(unsigned.signRequest)
+}
+
+// TypedDataJSON returns the typed data as the JSON parameter of eth_signTypedData_v4.
+func (unsigned *UnsignedAction) TypedDataJSON() ([]byte, error) {
+	return json.Marshal(unsigned.TypedData())
+}
+
+// Digest returns the EIP-712 hash signed by the wallet.
+func (unsigned *UnsignedAction) Digest() ([32]byte, error) {
+	return unsigned.signRequest.Digest()
+}
+
+// WithSignature assembles the exchange request with the hex signature returned by the wallet.
+// It returns the address that signed the typed data, which must be the user (user signed actions)
+// or the user or one of its agents (L1 actions), otherwise the exchange rejects the request.
+func (unsigned *UnsignedAction) WithSignature(signature string) (*ExchangeRequest, common.Address, error) {
+	digest, err := unsigned.Digest()
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	sig, signer, err := parseSignature(signature, digest)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	v, r, s, err := SignatureToVRS(sig)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	return unsigned.request(ToTypedSig(r, s, v)), signer, nil
+}
+
+func (unsigned *UnsignedAction) request(signature RsvSignature) *ExchangeRequest {
+	return &ExchangeRequest{
+		Action:       unsigned.Action,
+		Nonce:        unsigned.Nonce,
+		Signature:    signature,
+		VaultAddress: unsigned.VaultAddress,
+	}
+}
+
+// SubmitSigned sends the action signed outside of the client (see UnsignedAction).
+// The signature must be from the account address of the client, or one of its agents for L1 actions.
+func SubmitSigned[R any](api *ExchangeAPI, unsigned *UnsignedAction, signature string) (*R, error) {
+	request, signer, err := unsigned.WithSignature(signature)
+	if err != nil {
+		api.debug("Error reading signature: %s", err)
+		return nil, err
+	}
+	if _, userSigned := unsigned.Action.(UserSignedAction); userSigned && api.AccountAddress() != "" &&
+		signer != common.HexToAddress(api.AccountAddress()) {
+		return nil, fmt.Errorf("%w: signed by %s, expected %s", ErrSignerMismatch, signer, api.AccountAddress())
+	}
+	return MakeUniversalRequest[R](api, request)
+}
@@ -23,7 +23,7 @@ type IAPIService interface {
 	debug(format string, args ...interface{})
 	Request(path string, payload any) ([]byte, error)
 	Endpoint() string
-	KeyManager() *PKeyManager
+	Signer() Signer
 }
 
 // MakeUniversalRequest is a generic function that takes an
@@ -36,9 +36,6 @@ func MakeUniversalRequest[T any](api IAPIService, request any) (*T, error) {
 	if api == nil {
 		return nil, APIError{Message: "API not set"}
 	}
-	if api.Endpoint() == "/exchange" && api.KeyManager() == nil {
-		return nil, APIError{Message: "API key not set"}
-	}
 
 	response, err := api.Request(api.Endpoint(), request)
 	if err != nil {
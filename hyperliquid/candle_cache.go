@@ -0,0 +1,130 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// candleCache is the content of a candle cache file.
+// Ranges are the merged [from, to] time ranges that were fully fetched,
+// so ranges without trades are not requested again either.
+type candleCache struct {
+	path    string
+	Ranges  [][2]int64       `json:"ranges"`
+	Candles []CandleSnapshot `json:"candles"`
+}
+
+// candleCachePath returns the cache file of a coin and interval on a network, e.g. "dir/Mainnet_PURR-USDC_1h.json"
+func candleCachePath(dir string, isMainnet bool, coin string, interval CandleInterval) string {
+	network := "Testnet"
+	if isMainnet {
+		network = "Mainnet"
+	}
+	name := strings.NewReplacer("/", "-", "\\", "-").Replace(coin)
+	return filepath.Join(dir, fmt.Sprintf("%s_%s_%s.json", network, name, interval))
+}
+
+func loadCandleCache(dir string, isMainnet bool, coin string, interval CandleInterval) (*candleCache, error) {
+	cache := &candleCache{path: candleCachePath(dir, isMainnet, coin, interval)}
+	data, err := os.ReadFile(cache.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, cache); err != nil {
+		return nil, fmt.Errorf("invalid candle cache %s: %w", cache.path, err)
+	}
+	return cache, nil
+}
+
+// covers returns true if [from, to] was already fetched
+func (cache *candleCache) covers(from int64, to int64) bool {
+	for _, r := range cache.Ranges {
+		if r[0] <= from && to <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// add stores the candles closed before closedBefore and marks [from, to] as fetched.
+// Only the part of the range before the first candle still in progress is marked,
+// so that candle is fetched again once it's closed.
+func (cache *candleCache) add(candles []CandleSnapshot, from int64, to int64, interval CandleInterval, closedBefore int64) {
+	byOpenTime := make(map[int64]CandleSnapshot, len(cache.Candles)+len(candles))
+	for _, candle := range cache.Candles {
+		byOpenTime[candle.OpenTime] = candle
+	}
+	for _, candle := range candles {
+		if candle.CloseTime < closedBefore {
+			byOpenTime[candle.OpenTime] = candle
+		}
+	}
+	cache.Candles = cache.Candles[:0]
+	for _, candle := range byOpenTime {
+		cache.Candles = append(cache.Candles, candle)
+	}
+	sort.Slice(cache.Candles, func(i, j int) bool {
+		return cache.Candles[i].OpenTime < cache.Candles[j].OpenTime
+	})
+
+	// Without trades the candle in progress is missing from the snapshot,
+	// so the range also ends before the earliest time it could have opened
+	to = min(to, candleInProgressAfter(interval, closedBefore))
+	for _, candle := range candles {
+		if candle.CloseTime >= closedBefore {
+			to = min(to, candle.OpenTime-1)
+		}
+	}
+	if to < from {
+		return
+	}
+
+	// Merge the new range with the overlapping or adjacent ones
+	ranges := append(cache.Ranges, [2]int64{from, to})
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i][0] < ranges[j][0]
+	})
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r[0] <= last[1]+1 {
+			last[1] = max(last[1], r[1])
+			continue
+		}
+		merged = append(merged, r)
+	}
+	cache.Ranges = merged
+}
+
+// candleInProgressAfter returns the time after which a candle of the interval may still be in progress at closedBefore
+func candleInProgressAfter(interval CandleInterval, closedBefore int64) int64 {
+	if interval == Candle1M {
+		return time.UnixMilli(closedBefore).UTC().AddDate(0, -1, 0).UnixMilli()
+	}
+	return closedBefore - interval.Duration().Milliseconds()
+}
+
+func (cache *candleCache) save() error {
+	if err := os.MkdirAll(filepath.Dir(cache.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first so an interrupted write doesn't corrupt the cache
+	tmp := cache.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, cache.path)
+}
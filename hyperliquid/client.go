@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -18,9 +19,11 @@ import (
 // IsMainnet method returns true if the client is connected to the mainnet.
 // debug method enables debug mode.
 // SetPrivateKey method sets the private key for the client.
+// SetSigner method sets a signer that holds the key outside of the client.
 type IClient interface {
 	IAPIService
 	SetPrivateKey(privateKey string) error
+	SetSigner(signer Signer)
 	SetAccountAddress(address string)
 	AccountAddress() string
 	SetDebugActive()
@@ -30,17 +33,20 @@ type IClient interface {
 // Client is the default implementation of the Client interface.
 //
 // It contains the base URL of the HyperLiquid API, the HTTP client, the debug mode,
-// the network type, the private key, and the logger.
+// the network type, the signer, and the logger.
 // The debug method prints the debug messages.
 type Client struct {
-	baseUrl        string       // Base URL of the HyperLiquid API
-	privateKey     string       // Private key for the client
-	defualtAddress string       // Default address for the client
-	isMainnet      bool         // Network type
-	Debug          bool         // Debug mode
-	httpClient     *http.Client // HTTP client
-	keyManager     *PKeyManager // Private key manager
-	Logger         *log.Logger  // Logger for debug messages
+	baseUrl        string           // Base URL of the HyperLiquid API
+	defualtAddress string           // Default address for the client
+	isMainnet      bool             // Network type
+	Debug          bool             // Debug mode
+	httpClient     *http.Client     // HTTP client
+	keyManager     *PKeyManager     // Private key manager
+	signer         Signer           // Signer of the exchange actions
+	nonces         *NonceManager    // Nonces of the signers
+	clock          func() time.Time // Local time, time.Now by default
+	customClock    bool             // True once SetClock was called
+	Logger         *log.Logger      // Logger for debug messages
 }
 
 // Returns the private key manager connected to the API.
@@ -48,6 +54,54 @@ func (client *Client) KeyManager() *PKeyManager {
 	return client.keyManager
 }
 
+// Returns the signer of the exchange actions.
+func (client *Client) Signer() Signer {
+	return client.signer
+}
+
+// SetSigner sets the signer of the exchange actions, e.g. a KeystoreSigner or a RemoteSigner.
+// It replaces the key set with SetPrivateKey.
+func (client *Client) SetSigner(signer Signer) {
+	client.signer = signer
+	client.keyManager = nil
+}
+
+// SetKeyManager sets the private key manager, e.g. loaded with NewPKeyManagerFromKeystore
+// or NewPKeyManagerFromMnemonic, as the signer of the exchange actions.
+func (client *Client) SetKeyManager(keyManager *PKeyManager) {
+	client.keyManager = keyManager
+	client.signer = keyManager
+}
+
+// Close wipes the private key of the key manager.
+// The client can't sign exchange actions anymore.
+func (client *Client) Close() error {
+	if client.keyManager != nil {
+		return client.keyManager.Close()
+	}
+	return nil
+}
+
+// NonceManager returns the manager of the nonces used to sign exchange actions.
+func (client *Client) NonceManager() *NonceManager {
+	return client.nonces
+}
+
+// SetNonceManager sets the nonce manager of this client instead of the process-wide DefaultNonceManager,
+// e.g. one with a FileNonceStore to keep the nonces across restarts. The other clients keep their manager,
+// clients using the same signer in one process should be given the same one.
+func (client *Client) SetNonceManager(nonces *NonceManager) {
+	client.nonces = nonces
+}
+
+// SetClock sets the function returning the local time, used for nonces (e.g. a fixed time in tests).
+// The Date headers of the responses don't update the clock offset of DefaultNonceManager anymore,
+// use SetNonceManager to estimate the offset against this clock.
+func (client *Client) SetClock(clock func() time.Time) {
+	client.clock = clock
+	client.customClock = true
+}
+
 // getAPIURL returns the API URL based on the network type.
 func getURL(isMainnet bool) string {
 	if isMainnet {
@@ -71,10 +125,12 @@ func NewClient(isMainnet bool) *Client {
 		httpClient:     http.DefaultClient,
 		Debug:          false,
 		isMainnet:      isMainnet,
-		privateKey:     "",
 		defualtAddress: "",
 		Logger:         logger,
 		keyManager:     nil,
+		signer:         nil,
+		nonces:         DefaultNonceManager(),
+		clock:          time.Now,
 	}
 }
 
@@ -90,10 +146,13 @@ func (client *Client) SetPrivateKey(privateKey string) error {
 	if strings.HasPrefix(privateKey, "0x") {
 		privateKey = strings.TrimPrefix(privateKey, "0x") // remove 0x prefix from private key
 	}
-	client.privateKey = privateKey
-	var err error
-	client.keyManager, err = NewPKeyManager(privateKey)
-	return err
+	keyManager, err := NewPKeyManager(privateKey)
+	if err != nil {
+		client.SetSigner(nil)
+		return err
+	}
+	client.SetKeyManager(keyManager)
+	return nil
 }
 
 // Some methods need public address to gather info (from infoAPI).
@@ -140,11 +199,13 @@ func (client *Client) Request(endpoint string, payload any) ([]byte, error) {
 		return nil, err
 	}
 	request.Header.Set("Content-Type", "application/json")
+	sent := client.clock()
 	response, err := client.httpClient.Do(request)
 	if err != nil {
 		client.debug("Error client.httpClient.Do: %s", err)
 		return nil, err
 	}
+	client.observeServerTime(response, sent)
 	data, err := io.ReadAll(response.Body)
 	if err != nil {
 		return nil, err
@@ -166,3 +227,17 @@ func (client *Client) Request(endpoint string, payload any) ([]byte, error) {
 	}
 	return data, nil
 }
+
+// observeServerTime estimates the clock offset from the Date header of the response.
+// The header is truncated to the second, so the server time is half a second later on average.
+func (client *Client) observeServerTime(response *http.Response, sent time.Time) {
+	serverTime, err := http.ParseTime(response.Header.Get("Date"))
+	if err != nil {
+		return
+	}
+	if client.customClock && client.nonces == DefaultNonceManager() {
+		// The offset from another clock would shift the nonces of every client sharing the manager
+		return
+	}
+	client.nonces.ObserveServerTime(serverTime.Add(500*time.Millisecond), sent, client.clock())
+}
@@ -1,19 +1,33 @@
 package hyperliquid
 
+import "time"
+
 const GLOBAL_DEBUG = false // Default debug that is used in all tests
 
 // API constants
 const MAINNET_API_URL = "https://api.hyperliquid.xyz"
 const TESTNET_API_URL = "https://api.hyperliquid-testnet.xyz"
+const USER_FILLS_BY_TIME_LIMIT = 2000 // Max fills returned by a single userFillsByTime request
+const CANDLE_SNAPSHOT_LIMIT = 5000    // Max candles returned by a single candleSnapshot request
 
 // Execution constants
 const DEFAULT_SLIPPAGE = 0.005 // 0.5% default slippage
 const SPOT_MAX_DECIMALS = 8    // Default decimals for spot
 const PERP_MAX_DECIMALS = 6    // Default decimals for perp
 var USDC_SZ_DECIMALS = 2       // Default decimals for usdc that is used for withdraw
+const MIN_ORDER_VALUE = 10     // Minimum order value in USDC (size * price)
+
+// Funding constants
+const HL_FUNDING_INTERVAL_HOURS = 1  // Hyperliquid pays funding every hour
+const CEX_FUNDING_INTERVAL_HOURS = 8 // Default funding interval of Binance and Bybit
 
 // Signing constants
 const HYPERLIQUID_CHAIN_ID = 1337
 const VERIFYING_CONTRACT = "0x0000000000000000000000000000000000000000"
 const ARBITRUM_CHAIN_ID = 42161
 const ARBITRUM_TESTNET_CHAIN_ID = 421614
+const REMOTE_SIGNER_TIMEOUT = 10 * time.Second // Default timeout of a RemoteSigner request
+
+// Time constants
+const MILLISECONDS_PER_HOUR = 60 * 60 * 1000
+const MILLISECONDS_PER_YEAR = 365 * 24 * MILLISECONDS_PER_HOUR
@@ -4,7 +4,6 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"math"
 	"math/big"
 	"strconv"
 	"strings"
@@ -57,8 +56,8 @@ func OrderRequestToWire(req OrderRequest, meta map[string]AssetInfo, isSpot bool
 	return OrderWire{
 		Asset:      assetId,
 		IsBuy:      req.IsBuy,
-		LimitPx:    PriceToWire(req.LimitPx, maxDecimals, info.SzDecimals),
-		SizePx:     SizeToWire(req.Sz, info.SzDecimals),
+		LimitPx:    RoundPrice(req.LimitPx, maxDecimals, info.SzDecimals, req.PxRounding, req.IsBuy).String(),
+		SizePx:     orderSzToWire(req.Sz, info.SzDecimals, req.SzRounding),
 		ReduceOnly: req.ReduceOnly,
 		OrderType:  OrderTypeToWire(req.OrderType),
 		Cloid:      req.Cloid,
@@ -81,14 +80,22 @@ func ModifyOrderRequestToWire(req ModifyOrderRequest, meta map[string]AssetInfo,
 		Order: OrderWire{
 			Asset:      assetId,
 			IsBuy:      req.IsBuy,
-			LimitPx:    PriceToWire(req.LimitPx, maxDecimals, info.SzDecimals),
-			SizePx:     SizeToWire(req.Sz, info.SzDecimals),
+			LimitPx:    RoundPrice(req.LimitPx, maxDecimals, info.SzDecimals, req.PxRounding, req.IsBuy).String(),
+			SizePx:     orderSzToWire(req.Sz, info.SzDecimals, req.SzRounding),
 			ReduceOnly: req.ReduceOnly,
 			OrderType:  OrderTypeToWire(req.OrderType),
 		},
 	}
 }
 
+// Helper function to keep truncating sizes without decimals for RoundNearest (see DecimalSizeToWire)
+func orderSzToWire(sz Decimal, szDecimals int, mode RoundingMode) string {
+	if mode == RoundNearest {
+		return DecimalSizeToWire(sz, szDecimals)
+	}
+	return RoundSize(sz, szDecimals, mode).String()
+}
+
 func OrderTypeToWire(orderType OrderType) OrderTypeWire {
 	if orderType.Limit != nil {
 		return OrderTypeWire{
@@ -149,74 +156,99 @@ func pow10(exp int) float64 {
 //   - At most 5 significant figures,
 //   - And no more than (maxDecimals - szDecimals) decimal places.
 //
-// Integer prices are returned as is.
+// The price is rounded to the nearest allowed value. Integer prices with more
+// than 5 significant figures are rounded too (e.g. 123456 becomes 123460).
+// Integer prices used to be returned unchanged, callers relying on that now get a different price.
+// See PriceToWireRounded for directional rounding.
 func PriceToWire(x float64, maxDecimals, szDecimals int) string {
-	// If the price is an integer, return it without decimals.
-	if x == math.Trunc(x) {
-		return strconv.FormatInt(int64(x), 10)
-	}
+	return DecimalPriceToWire(NewDecimalFromFloat(x), maxDecimals, szDecimals)
+}
+
+// DecimalPriceToWire is the exact version of PriceToWire for Decimal prices.
+// Like PriceToWire it rounds integer prices to 5 significant figures, they used to be returned unchanged.
+func DecimalPriceToWire(x Decimal, maxDecimals, szDecimals int) string {
+	return RoundPrice(x, maxDecimals, szDecimals, RoundNearest, true).String()
+}
 
+// PriceToWireRounded is the same as PriceToWire but rounds the price with the given mode.
+// The side is used to resolve RoundPassive and RoundAggressive.
+//
+//	PriceToWireRounded(2500.55, 6, 4, RoundPassive, true) // "2500.5"
+//	PriceToWireRounded(2500.55, 6, 4, RoundPassive, false) // "2500.6"
+func PriceToWireRounded(x float64, maxDecimals, szDecimals int, mode RoundingMode, isBuy bool) string {
+	return RoundPrice(NewDecimalFromFloat(x), maxDecimals, szDecimals, mode, isBuy).String()
+}
+
+// RoundPrice rounds the price to at most 5 significant figures and
+// (maxDecimals - szDecimals) decimal places with the given mode.
+// The side is used to resolve RoundPassive and RoundAggressive.
+func RoundPrice(x Decimal, maxDecimals, szDecimals int, mode RoundingMode, isBuy bool) Decimal {
+	if x.IsZero() {
+		return x
+	}
 	// Rule 1: The tick rule – maximum decimals allowed is (maxDecimals - szDecimals).
 	allowedTick := maxDecimals - szDecimals
 
 	// Rule 2: The significant figures rule – at most 5 significant digits.
-	var allowedSig int
-	if x >= 1 {
-		// Count digits in the integer part.
-		digits := int(math.Floor(math.Log10(x))) + 1
-		allowedSig = 5 - digits
-		if allowedSig < 0 {
-			allowedSig = 0
-		}
-	} else {
-		// For x < 1, determine the effective exponent.
-		exponent := int(math.Ceil(-math.Log10(x)))
-		allowedSig = 4 + exponent
-	}
+	// It is negative for prices above 99999 (e.g. -1 rounds 123456 to tens).
+	allowedSig := 4 - significantExponent(x.Abs())
 
 	// Final allowed decimals is the minimum of the tick rule and the significant figures rule.
-	allowedDecimals := allowedTick
-	if allowedSig < allowedDecimals {
-		allowedDecimals = allowedSig
-	}
-	if allowedDecimals < 0 {
-		allowedDecimals = 0
-	}
-
-	// Round the price to allowedDecimals decimals.
-	factor := pow10(allowedDecimals)
-	rounded := math.Round(x*factor) / factor
-
-	// Format the number with fixed precision.
-	s := strconv.FormatFloat(rounded, 'f', allowedDecimals, 64)
-	// Only trim trailing zeros if the formatted string contains a decimal point.
-	if strings.Contains(s, ".") {
-		s = strings.TrimRight(s, "0")
-		s = strings.TrimRight(s, ".")
-	}
-	return s
+	return roundDecimal(x, int32(min(allowedTick, allowedSig)), mode.ForSide(isBuy))
 }
 
 // SizeToWire converts a size value to its string representation,
-// rounding it to exactly szDecimals decimals.
+// rounding it to the nearest value with szDecimals decimals.
+// Sizes of assets without decimals are truncated.
 // Integer sizes are returned without decimals.
+// See SizeToWireRounded for directional rounding.
 func SizeToWire(x float64, szDecimals int) string {
-	// Return integer sizes without decimals.
+	return DecimalSizeToWire(NewDecimalFromFloat(x), szDecimals)
+}
+
+// DecimalSizeToWire is the exact version of SizeToWire for Decimal sizes.
+func DecimalSizeToWire(x Decimal, szDecimals int) string {
+	// Sizes of assets without decimals are truncated.
 	if szDecimals == 0 {
-		return strconv.FormatInt(int64(x), 10)
-	}
-	// Return integer sizes directly.
-	if x == math.Trunc(x) {
-		return strconv.FormatInt(int64(x), 10)
+		return x.Truncate(0).String()
 	}
+	return RoundSize(x, szDecimals, RoundNearest).String()
+}
 
-	// Round the size value to szDecimals decimals.
-	factor := pow10(szDecimals)
-	rounded := math.Round(x*factor) / factor
+// SizeToWireRounded is the same as SizeToWire but rounds the size with the given mode.
+// Use RoundFloor (or RoundPassive) to never exceed the available balance.
+//
+//	SizeToWireRounded(0.12345, 4, RoundFloor) // "0.1234"
+func SizeToWireRounded(x float64, szDecimals int, mode RoundingMode) string {
+	return RoundSize(NewDecimalFromFloat(x), szDecimals, mode).String()
+}
 
-	// Format with fixed precision then trim any trailing zeros and the decimal point.
-	s := strconv.FormatFloat(rounded, 'f', szDecimals, 64)
-	return strings.TrimRight(strings.TrimRight(s, "0"), ".")
+// RoundSize rounds the size to szDecimals decimal places with the given mode.
+// RoundPassive rounds toward zero and RoundAggressive away from zero.
+func RoundSize(x Decimal, szDecimals int, mode RoundingMode) Decimal {
+	switch mode {
+	case RoundPassive:
+		return x.Truncate(int32(szDecimals))
+	case RoundAggressive:
+		if x.IsNegative() {
+			return x.RoundFloor(int32(szDecimals))
+		}
+		return x.RoundCeil(int32(szDecimals))
+	}
+	return roundDecimal(x, int32(szDecimals), mode)
+}
+
+// Helper function to round x to the given decimal places, places can be negative.
+// Passive and aggressive modes must be resolved with ForSide before.
+func roundDecimal(x Decimal, places int32, mode RoundingMode) Decimal {
+	switch mode {
+	case RoundFloor:
+		return x.RoundFloor(places)
+	case RoundCeil:
+		return x.RoundCeil(places)
+	default:
+		return x.Round(places)
+	}
 }
 
 // To sign raw messages via EIP-712
@@ -1,6 +1,7 @@
 package hyperliquid
 
 import (
+	"encoding/json"
 	"testing"
 )
 
@@ -109,3 +110,105 @@ func TestConvert_PriceToWire(t *testing.T) {
 		})
 	}
 }
+
+func TestConvert_DecimalToWire(t *testing.T) {
+	testCases := []struct {
+		name     string
+		px       string
+		sz       string
+		maxDec   int
+		szDec    int
+		expectPx string
+		expectSz string
+	}{
+		{"Half cent size", "2500.5", "1.005", 6, 2, "2500.5", "1.01"},
+		{"Small price", "0.000123456789", "12345.6", 8, 0, "0.00012346", "12345"},
+		{"Tick rule", "1.23456789", "0.123456", 6, 4, "1.23", "0.1235"},
+		{"Sig figs rule", "12345.6789", "10", 6, 1, "12346", "10"},
+		{"Long decimal", "95001.123456789123456789", "0.00001", 6, 5, "95001", "0.00001"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			px := DecimalPriceToWire(MustDecimal(tc.px), tc.maxDec, tc.szDec)
+			if px != tc.expectPx {
+				t.Errorf("DecimalPriceToWire(%s) = %v, want %v", tc.px, px, tc.expectPx)
+			}
+			sz := DecimalSizeToWire(MustDecimal(tc.sz), tc.szDec)
+			if sz != tc.expectSz {
+				t.Errorf("DecimalSizeToWire(%s) = %v, want %v", tc.sz, sz, tc.expectSz)
+			}
+		})
+	}
+}
+
+func TestConvert_RoundingModes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		px       float64
+		isBuy    bool
+		mode     RoundingMode
+		expected string
+	}{
+		{"Nearest", 2500.55, true, RoundNearest, "2500.6"},
+		{"Passive buy", 2500.55, true, RoundPassive, "2500.5"},
+		{"Passive sell", 2500.55, false, RoundPassive, "2500.6"},
+		{"Aggressive buy", 2500.51, true, RoundAggressive, "2500.6"},
+		{"Aggressive sell", 2500.59, false, RoundAggressive, "2500.5"},
+		{"Floor", 2500.59, false, RoundFloor, "2500.5"},
+		{"Ceil", 2500.51, true, RoundCeil, "2500.6"},
+		{"Integer above 5 sig figs", 123456, true, RoundNearest, "123460"},
+		{"Integer above 5 sig figs passive sell", 123451, false, RoundPassive, "123460"},
+		{"Integer above 5 sig figs floor", 123459, true, RoundFloor, "123450"},
+		{"Already valid", 2500.5, false, RoundCeil, "2500.5"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := PriceToWireRounded(tc.px, 6, 4, tc.mode, tc.isBuy)
+			if res != tc.expected {
+				t.Errorf("PriceToWireRounded(%v) = %v, want %v", tc.px, res, tc.expected)
+			}
+		})
+	}
+
+	sizeCases := []struct {
+		sz       float64
+		mode     RoundingMode
+		expected string
+	}{
+		{0.12345, RoundNearest, "0.1235"},
+		{0.12345, RoundFloor, "0.1234"},
+		{0.12341, RoundCeil, "0.1235"},
+		{0.12349, RoundPassive, "0.1234"},
+		{0.12341, RoundAggressive, "0.1235"},
+	}
+	for _, tc := range sizeCases {
+		res := SizeToWireRounded(tc.sz, 4, tc.mode)
+		if res != tc.expected {
+			t.Errorf("SizeToWireRounded(%v, %v) = %v, want %v", tc.sz, tc.mode, res, tc.expected)
+		}
+	}
+
+	meta := map[string]AssetInfo{"ETH": {SzDecimals: 4, AssetId: 1}}
+	orderType := OrderType{Limit: &LimitOrderType{Tif: TifAlo}}
+	wire := OrderRequestToWire(NewOrderRequest("ETH", -0.12345, 2500.55, orderType, false).WithRounding(RoundPassive, RoundFloor), meta, false)
+	if wire.LimitPx != "2500.6" || wire.SizePx != "0.1234" {
+		t.Errorf("OrderRequestToWire() = %s @ %s, want 0.1234 @ 2500.6", wire.SizePx, wire.LimitPx)
+	}
+}
+
+func TestConvert_DecodeDecimal(t *testing.T) {
+	data := `{"coin":"ETH","entryPx":"3012.123456789012345","szi":"-0.1234567890123456789","liquidationPx":null}`
+	var position Position
+	if err := json.Unmarshal([]byte(data), &position); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if position.EntryPx.String() != "3012.123456789012345" {
+		t.Errorf("position.EntryPx = %v, want %v", position.EntryPx, "3012.123456789012345")
+	}
+	if position.Szi.String() != "-0.1234567890123456789" {
+		t.Errorf("position.Szi = %v, want %v", position.Szi, "-0.1234567890123456789")
+	}
+	if !position.LiquidationPx.IsZero() {
+		t.Errorf("position.LiquidationPx = %v, want 0", position.LiquidationPx)
+	}
+}
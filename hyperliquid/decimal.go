@@ -0,0 +1,35 @@
+package hyperliquid
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// Decimal is an arbitrary-precision decimal number used for prices and sizes.
+// The API's string numbers (e.g. "95001.5") are decoded without loss and encoded back as strings.
+//
+// All the prices, sizes, amounts and rates of the response types are Decimal, except the
+// portfolio history (TimeValue and PortfolioHistory.Vlm) which is kept as float64 for the statistics of stats.go.
+// Derived values like annualized funding rates are float64 too.
+type Decimal = decimal.Decimal
+
+// NewDecimalFromFloat converts a float64 to a Decimal.
+// The shortest decimal representation of the float is used, so 0.1 becomes exactly 0.1.
+func NewDecimalFromFloat(x float64) Decimal {
+	return decimal.NewFromFloat(x)
+}
+
+// NewDecimalFromString parses a decimal string like "95001.5" or "1e-4"
+func NewDecimalFromString(s string) (Decimal, error) {
+	return decimal.NewFromString(s)
+}
+
+// MustDecimal is like NewDecimalFromString but panics if the string is not a valid decimal.
+// It is meant for constants and tests.
+func MustDecimal(s string) Decimal {
+	return decimal.RequireFromString(s)
+}
+
+// significantExponent returns the exponent of the most significant digit (e.g. 2 for 123.45, -2 for 0.0123)
+func significantExponent(x Decimal) int {
+	return x.NumDigits() + int(x.Exponent()) - 1
+}
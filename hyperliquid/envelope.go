@@ -0,0 +1,169 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrEnvelopeExpired is returned when a signing envelope is used after its expiry.
+var ErrEnvelopeExpired = errors.New("signing envelope is expired")
+
+// SigningEnvelope is an exchange action prepared by an online host to be signed on an offline (air-gapped) host.
+// Any action can be sent in an envelope, including withdrawals and transfers.
+//
+// The online host creates the envelope with NewSigningEnvelope and sends its JSON to the offline host.
+// The offline host reads it with ParseSigningEnvelope, checks it with Verify, shows it to the operator
+// and signs it with Sign. It never needs the network.
+// The online host reads the signed envelope back and sends it with SubmitEnvelope, which checks the signature first.
+//
+// The offline host rebuilds the signed digest from the action itself, so it signs exactly what it shows.
+type SigningEnvelope struct {
+	Action       json.RawMessage `json:"action"`
+	Nonce        uint64          `json:"nonce"`
+	VaultAddress *string         `json:"vaultAddress,omitempty"`
+	Network      string          `json:"network"`          // "Mainnet" or "Testnet"
+	ExpiresAt    int64           `json:"expiresAt"`        // Unix time in milliseconds, checked by both hosts (not by the exchange)
+	Signer       string          `json:"signer,omitempty"` // Address expected to sign, empty if any signer is accepted
+	Signature    *RsvSignature   `json:"signature,omitempty"`
+}
+
+// NewSigningEnvelope puts a prepared action (see PrepareAction) in an envelope that expires after ttl.
+// The expected signer is the signer of the client, or the account address for user signed actions.
+func (api *ExchangeAPI) NewSigningEnvelope(unsigned *UnsignedAction, ttl time.Duration) (*SigningEnvelope, error) {
+	action, err := json.Marshal(unsigned.Action)
+	if err != nil {
+		return nil, err
+	}
+	envelope := &SigningEnvelope{
+		Action:       action,
+		Nonce:        unsigned.Nonce,
+		VaultAddress: unsigned.VaultAddress,
+		Network:      getNetworkName(api.IsMainnet()),
+		ExpiresAt:    api.clock().Add(ttl).UnixMilli(),
+	}
+	if api.Signer() != nil {
+		envelope.Signer = api.Signer().Address().Hex()
+	} else if _, userSigned := unsigned.Action.(UserSignedAction); userSigned {
+		envelope.Signer = api.AccountAddress()
+	}
+	return envelope, nil
+}
+
+// ParseSigningEnvelope reads the JSON of a signing envelope.
+func ParseSigningEnvelope(data []byte) (*SigningEnvelope, error) {
+	var envelope SigningEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid signing envelope: %w", err)
+	}
+	if len(envelope.Action) == 0 {
+		return nil, APIError{Message: "Signing envelope has no action"}
+	}
+	if envelope.Network != getNetworkName(true) && envelope.Network != getNetworkName(false) {
+		return nil, APIError{Message: fmt.Sprintf("Invalid network in signing envelope: %q", envelope.Network)}
+	}
+	if envelope.Signer != "" && !common.IsHexAddress(envelope.Signer) {
+		return nil, APIError{Message: fmt.Sprintf("Invalid signer in signing envelope: %q", envelope.Signer)}
+	}
+	return &envelope, nil
+}
+
+// IsMainnet returns true if the action is for the mainnet.
+func (envelope *SigningEnvelope) IsMainnet() bool {
+	return envelope.Network == getNetworkName(true)
+}
+
+// ActionType returns the "type" field of the action, e.g. "withdraw3".
+func (envelope *SigningEnvelope) ActionType() string {
+	var action struct {
+		Type string `json:"type"`
+	}
+	data := envelope.Action
+	// Multi-sig payload of an L1 action: [multiSigUser, outerSigner, action]
+	var payload []json.RawMessage
+	if json.Unmarshal(data, &payload) == nil && len(payload) == 3 {
+		data = payload[2]
+	}
+	json.Unmarshal(data, &action)
+	return action.Type
+}
+
+// Request returns the exchange request of the envelope. Its signature is empty until the envelope is signed.
+func (envelope *SigningEnvelope) Request() ExchangeRequest {
+	request := ExchangeRequest{
+		Action:       envelope.Action,
+		Nonce:        envelope.Nonce,
+		VaultAddress: envelope.VaultAddress,
+	}
+	if envelope.Signature != nil {
+		request.Signature = *envelope.Signature
+	}
+	return request
+}
+
+// Verify checks the envelope for the network of the host at the time now.
+// It returns ErrNetworkMismatch for the other network and ErrEnvelopeExpired after its expiry.
+// The action must be valid for its type (e.g. the network and nonce fields of user signed actions).
+// If the envelope is signed, the signature must be from the expected signer (ErrSignerMismatch otherwise).
+func (envelope *SigningEnvelope) Verify(isMainnet bool, now time.Time) error {
+	if envelope.Network != getNetworkName(isMainnet) {
+		return fmt.Errorf("%w: envelope is for %s, expected %s", ErrNetworkMismatch, envelope.Network, getNetworkName(isMainnet))
+	}
+	if now.UnixMilli() > envelope.ExpiresAt {
+		return fmt.Errorf("%w: expired at %s", ErrEnvelopeExpired, time.UnixMilli(envelope.ExpiresAt).UTC().Format(time.RFC3339))
+	}
+	request := envelope.Request()
+	if _, err := buildRequestSignRequest(request, isMainnet); err != nil {
+		return err
+	}
+	if envelope.Signature == nil {
+		return nil
+	}
+	if envelope.Signer == "" {
+		_, err := RecoverSigner(request, isMainnet)
+		return err
+	}
+	return VerifySigner(request, isMainnet, common.HexToAddress(envelope.Signer))
+}
+
+// Sign verifies the envelope (see Verify) and signs it with the signer, which must be the expected signer if any.
+func (envelope *SigningEnvelope) Sign(signer Signer, now time.Time) error {
+	if envelope.Signature != nil {
+		return APIError{Message: "Signing envelope is already signed"}
+	}
+	isMainnet := envelope.IsMainnet()
+	if err := envelope.Verify(isMainnet, now); err != nil {
+		return err
+	}
+	if envelope.Signer != "" && common.HexToAddress(envelope.Signer) != signer.Address() {
+		return fmt.Errorf("%w: signer is %s, expected %s", ErrSignerMismatch, signer.Address(), envelope.Signer)
+	}
+	signRequest, err := buildRequestSignRequest(envelope.Request(), isMainnet)
+	if err != nil {
+		return err
+	}
+	v, r, s, err := SignTypedData(signer, signRequest)
+	if err != nil {
+		return err
+	}
+	signature := ToTypedSig(r, s, v)
+	envelope.Signature = &signature
+	envelope.Signer = signer.Address().Hex()
+	return nil
+}
+
+// SubmitEnvelope verifies the signed envelope for the network of the client (see Verify) and sends it to the exchange.
+func SubmitEnvelope[R any](api *ExchangeAPI, envelope *SigningEnvelope) (*R, error) {
+	if envelope.Signature == nil {
+		return nil, APIError{Message: "Signing envelope is not signed"}
+	}
+	if err := envelope.Verify(api.IsMainnet(), api.clock()); err != nil {
+		api.debug("Error verifying signing envelope: %s", err)
+		return nil, err
+	}
+	request := envelope.Request()
+	return MakeUniversalRequest[R](api, &request)
+}
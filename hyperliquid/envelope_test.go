@@ -0,0 +1,126 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSigningEnvelope_OfflineSigning(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+	wallet, _ := NewPKeyManager(testPrivateKey)
+	online, actionType := getVerifyingExchangeAPI(t, wallet.Address())
+	// The online host has no key
+	online.signer = nil
+	online.SetAccountAddress(wallet.Address().Hex())
+	online.SetClock(func() time.Time { return now })
+
+	orderType := OrderType{Limit: &LimitOrderType{Tif: TifGtc}}
+	prepare := map[string]func() (*UnsignedAction, error){
+		"order": func() (*UnsignedAction, error) {
+			return online.PrepareBulkOrders([]OrderRequest{NewOrderRequest("ETH", 0.1, 2500, orderType, false)}, GroupingNa, false)
+		},
+		"withdraw3": func() (*UnsignedAction, error) {
+			return online.PrepareWithdraw("0x0000000000000000000000000000000000000002", 10)
+		},
+		"usdSend": func() (*UnsignedAction, error) {
+			return online.PrepareUsdSend("0x0000000000000000000000000000000000000002", 10)
+		},
+	}
+	for name, fn := range prepare {
+		t.Run(name, func(t *testing.T) {
+			unsigned, err := fn()
+			if err != nil {
+				t.Fatalf("Prepare() error = %v", err)
+			}
+			envelope, err := online.NewSigningEnvelope(unsigned, time.Minute)
+			if err != nil {
+				t.Fatalf("NewSigningEnvelope() error = %v", err)
+			}
+			data, _ := json.Marshal(envelope)
+
+			// Offline host
+			offline, err := ParseSigningEnvelope(data)
+			if err != nil {
+				t.Fatalf("ParseSigningEnvelope() error = %v", err)
+			}
+			if offline.ActionType() != name {
+				t.Errorf("ActionType() = %s, want %s", offline.ActionType(), name)
+			}
+			if err := offline.Verify(false, now); err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if err := offline.Sign(wallet, now.Add(30*time.Second)); err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+			data, _ = json.Marshal(offline)
+
+			// Online host
+			signed, err := ParseSigningEnvelope(data)
+			if err != nil {
+				t.Fatalf("ParseSigningEnvelope(signed) error = %v", err)
+			}
+			res, err := SubmitEnvelope[DefaultExchangeResponse](online, signed)
+			if err != nil {
+				t.Fatalf("SubmitEnvelope() error = %v", err)
+			}
+			if *actionType != name || res.Status != "ok" {
+				t.Errorf("SubmitEnvelope() = %+v for %s", res, *actionType)
+			}
+		})
+	}
+}
+
+func TestSigningEnvelope_Rejected(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+	wallet, _ := NewPKeyManager(testPrivateKey)
+	other, _ := NewPKeyManager("1111111111111111111111111111111111111111111111111111111111111111")
+	api := getTestSigningExchangeAPI(t, false)
+	api.SetClock(func() time.Time { return now })
+	newEnvelope := func() *SigningEnvelope {
+		unsigned, err := api.PrepareWithdraw("0x0000000000000000000000000000000000000002", 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		envelope, err := api.NewSigningEnvelope(unsigned, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return envelope
+	}
+
+	if err := newEnvelope().Verify(true, now); !errors.Is(err, ErrNetworkMismatch) {
+		t.Errorf("Verify(mainnet) error = %v, want ErrNetworkMismatch", err)
+	}
+	if err := newEnvelope().Sign(wallet, now.Add(2*time.Minute)); !errors.Is(err, ErrEnvelopeExpired) {
+		t.Errorf("Sign(expired) error = %v, want ErrEnvelopeExpired", err)
+	}
+	if err := newEnvelope().Sign(other, now); !errors.Is(err, ErrSignerMismatch) {
+		t.Errorf("Sign(other key) error = %v, want ErrSignerMismatch", err)
+	}
+
+	// The nonce signed in the action must be the nonce of the request
+	envelope := newEnvelope()
+	envelope.Nonce++
+	if err := envelope.Verify(false, now); err == nil {
+		t.Errorf("Verify(other nonce) error = nil, want error")
+	}
+
+	// The action is changed after it was signed
+	envelope = newEnvelope()
+	if err := envelope.Sign(wallet, now); err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	var action WithdrawAction
+	json.Unmarshal(envelope.Action, &action)
+	action.Amount = "1000"
+	envelope.Action, _ = json.Marshal(action)
+	if _, err := SubmitEnvelope[WithdrawResponse](api, envelope); !errors.Is(err, ErrSignerMismatch) {
+		t.Errorf("SubmitEnvelope(changed action) error = %v, want ErrSignerMismatch", err)
+	}
+
+	if _, err := ParseSigningEnvelope([]byte(`{"action":{"type":"usdSend"},"nonce":1,"network":"Devnet"}`)); err == nil {
+		t.Errorf("ParseSigningEnvelope(invalid network) error = nil, want error")
+	}
+}
@@ -29,8 +29,8 @@ func TestExchangeAPI_BuildOrder(t *testing.T) {
 	orderRequest := OrderRequest{
 		Coin:       coin,
 		IsBuy:      isBuy,
-		Sz:         math.Abs(size),
-		LimitPx:    price,
+		Sz:         NewDecimalFromFloat(math.Abs(size)),
+		LimitPx:    NewDecimalFromFloat(price),
 		OrderType:  orderType,
 		ReduceOnly: false,
 	}
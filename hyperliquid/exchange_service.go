@@ -1,9 +1,14 @@
 package hyperliquid
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
 
@@ -33,11 +38,16 @@ type IExchangeAPI interface {
 // Implement the IExchangeAPI interface.
 type ExchangeAPI struct {
 	Client
-	infoAPI      *InfoAPI
-	address      string
-	baseEndpoint string
-	meta         map[string]AssetInfo
-	spotMeta     map[string]AssetInfo
+	infoAPI        *InfoAPI
+	address        string
+	baseEndpoint   string
+	meta           map[string]AssetInfo
+	spotMeta       map[string]AssetInfo
+	capOrderSize   bool
+	validateOrders bool
+	pxRounding     RoundingMode
+	szRounding     RoundingMode
+	vaultAddress   string
 }
 
 // NewExchangeAPI creates a new default ExchangeAPI.
@@ -75,6 +85,77 @@ func (api *ExchangeAPI) Endpoint() string {
 	return api.baseEndpoint
 }
 
+// SetVaultAddress makes the API trade for a vault or a sub-account that the agent key manages.
+// L1 actions (orders, cancels, leverage...) are signed and sent with the vault address.
+// Set an empty address to trade for the account itself.
+func (api *ExchangeAPI) SetVaultAddress(address string) {
+	api.vaultAddress = address
+}
+
+// Returns the vault address of the API, nil if it's not set
+func (api *ExchangeAPI) getVaultAddress() *string {
+	if api.vaultAddress == "" {
+		return nil
+	}
+	vaultAddress := api.vaultAddress
+	return &vaultAddress
+}
+
+// SetCapOrderSize makes MarketOrder and LimitOrder reduce the requested size to the
+// maximum size the account can trade at its current leverage (see GetActiveAssetData),
+// instead of sending an order that would be rejected for insufficient margin.
+// Reduce only orders are never capped.
+func (api *ExchangeAPI) SetCapOrderSize(active bool) {
+	api.capOrderSize = active
+}
+
+// SetRoundingModes sets how the order builders (MarketOrder, LimitOrder, ClosePosition, CreateUnsigned*Order...)
+// round prices and sizes to the tick and lot size. The default is RoundNearest for both.
+//
+//	SetRoundingModes(RoundPassive, RoundFloor) // Quote away from the market and never exceed the balance
+func (api *ExchangeAPI) SetRoundingModes(px RoundingMode, sz RoundingMode) {
+	api.pxRounding = px
+	api.szRounding = sz
+}
+
+// SetValidateOrders makes BulkOrders and BulkModifyOrders check the orders with ValidateOrderRequests
+// before signing them. Invalid orders are returned as OrderValidationError and never sent.
+func (api *ExchangeAPI) SetValidateOrders(active bool) {
+	api.validateOrders = active
+}
+
+// ValidateOrders checks the orders with ValidateOrderRequests using the meta of the exchange.
+func (api *ExchangeAPI) ValidateOrders(requests []OrderRequest, isSpot bool) error {
+	if isSpot {
+		return ValidateOrderRequests(requests, api.spotMeta, isSpot)
+	}
+	return ValidateOrderRequests(requests, api.meta, isSpot)
+}
+
+// Helper function to cap the order size to the maximum tradable size of the account.
+// The capped size is rounded down to the asset's size decimals.
+func (api *ExchangeAPI) capSize(coin string, isBuy bool, size Decimal) (Decimal, error) {
+	info, ok := api.meta[coin]
+	if !ok {
+		return Decimal{}, APIError{Message: fmt.Sprintf("Unknown coin %s, can't round its size", coin)}
+	}
+	data, err := api.infoAPI.GetActiveAssetData(api.AccountAddress(), coin)
+	if err != nil {
+		api.debug("Error getting active asset data: %s", err)
+		return Decimal{}, err
+	}
+	maxSz := data.MaxTradeSz(isBuy)
+	if size.LessThanOrEqual(maxSz) {
+		return size, nil
+	}
+	capped := maxSz.RoundFloor(int32(info.SzDecimals))
+	if !capped.IsPositive() {
+		return Decimal{}, APIError{Message: fmt.Sprintf("No size available to trade for %s", coin)}
+	}
+	api.debug("Order size for %s capped from %s to %s", coin, size, capped)
+	return capped, nil
+}
+
 // Helper function to calculate the slippage price based on the market price.
 func (api *ExchangeAPI) SlippagePrice(coin string, isBuy bool, slippage float64) float64 {
 	marketPx, err := api.infoAPI.GetMartketPx(coin)
@@ -96,6 +177,40 @@ func (api *ExchangeAPI) SlippagePriceSpot(coin string, isBuy bool, slippage floa
 	return slippagePrice
 }
 
+// SetNonceManager sets the manager of the nonces of the exchange and of its info API,
+// so the Date headers of the info requests keep updating the clock offset.
+func (api *ExchangeAPI) SetNonceManager(nonces *NonceManager) {
+	api.Client.SetNonceManager(nonces)
+	if api.infoAPI != nil {
+		api.infoAPI.SetNonceManager(nonces)
+	}
+}
+
+// SetClock sets the function returning the local time of the exchange and of its info API.
+func (api *ExchangeAPI) SetClock(clock func() time.Time) {
+	api.Client.SetClock(clock)
+	if api.infoAPI != nil {
+		api.infoAPI.SetClock(clock)
+	}
+}
+
+// Helper function to get the next nonce of the signer.
+// Without a signer (e.g. actions signed by an external wallet) the nonces of the account address are used.
+func (api *ExchangeAPI) nextNonce() (uint64, error) {
+	var signer common.Address
+	if api.Signer() != nil {
+		signer = api.Signer().Address()
+	} else if api.AccountAddress() != "" {
+		signer = common.HexToAddress(api.AccountAddress())
+	}
+	nonce, err := api.NonceManager().NextAt(signer, api.clock())
+	if err != nil {
+		api.debug("Error getting nonce: %s", err)
+		return 0, err
+	}
+	return nonce, nil
+}
+
 // Helper function to get the chain params based on the network type.
 func (api *ExchangeAPI) getChainParams() (string, string) {
 	if api.IsMainnet() {
@@ -105,12 +220,16 @@ func (api *ExchangeAPI) getChainParams() (string, string) {
 }
 
 // Build bulk orders EIP712 message
+// The nonce is not returned, use PrepareBulkOrders to send the orders signed by an external wallet.
 func (api *ExchangeAPI) BuildBulkOrdersEIP712(requests []OrderRequest, grouping Grouping) (apitypes.TypedData, error) {
 	var wires []OrderWire
 	for _, req := range requests {
 		wires = append(wires, OrderRequestToWire(req, api.meta, false))
 	}
-	timestamp := GetNonce()
+	timestamp, err := api.nextNonce()
+	if err != nil {
+		return apitypes.TypedData{}, err
+	}
 	action := OrderWiresToOrderAction(wires, grouping)
 	srequest, err := api.BuildEIP712Message(action, timestamp)
 	if err != nil {
@@ -132,6 +251,15 @@ func (api *ExchangeAPI) BuildOrderEIP712(request OrderRequest, grouping Grouping
 // Place orders in bulk
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#place-an-order
 func (api *ExchangeAPI) BulkOrders(requests []OrderRequest, grouping Grouping, isSpot bool) (*OrderResponse, error) {
+	action, err := api.bulkOrdersAction(requests, grouping, isSpot)
+	if err != nil {
+		return nil, err
+	}
+	return Execute(api, action)
+}
+
+// Helper function to build the action of BulkOrders
+func (api *ExchangeAPI) bulkOrdersAction(requests []OrderRequest, grouping Grouping, isSpot bool) (PlaceOrderAction, error) {
 	var wires []OrderWire
 	var meta map[string]AssetInfo
 	if isSpot {
@@ -139,78 +267,69 @@ func (api *ExchangeAPI) BulkOrders(requests []OrderRequest, grouping Grouping, i
 	} else {
 		meta = api.meta
 	}
+	if api.validateOrders {
+		if err := ValidateOrderRequests(requests, meta, isSpot); err != nil {
+			api.debug("Error validating orders: %s", err)
+			return PlaceOrderAction{}, err
+		}
+	}
 	for _, req := range requests {
 		wires = append(wires, OrderRequestToWire(req, meta, isSpot))
 	}
-	timestamp := GetNonce()
-	action := OrderWiresToOrderAction(wires, grouping)
-	v, r, s, err := api.SignL1Action(action, timestamp)
-	if err != nil {
-		api.debug("Error signing L1 action: %s", err)
-		return nil, err
-	}
-	request := ExchangeRequest{
-		Action:       action,
-		Nonce:        timestamp,
-		Signature:    ToTypedSig(r, s, v),
-		VaultAddress: nil,
-	}
-	return MakeUniversalRequest[OrderResponse](api, request)
+	return OrderWiresToOrderAction(wires, grouping), nil
 }
 
 // Cancel order(s)
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#cancel-order-s
 func (api *ExchangeAPI) BulkCancelOrders(cancels []CancelOidWire) (*OrderResponse, error) {
-	timestamp := GetNonce()
-	action := CancelOidOrderAction{
+	return Execute(api, bulkCancelOrdersAction(cancels))
+}
+
+// Helper function to build the action of BulkCancelOrders
+func bulkCancelOrdersAction(cancels []CancelOidWire) CancelOidOrderAction {
+	return CancelOidOrderAction{
 		Type:    "cancel",
 		Cancels: cancels,
 	}
-	v, r, s, err := api.SignL1Action(action, timestamp)
-	if err != nil {
-		api.debug("Error signing L1 action: %s", err)
-		return nil, err
-	}
-	request := ExchangeRequest{
-		Action:       action,
-		Nonce:        timestamp,
-		Signature:    ToTypedSig(r, s, v),
-		VaultAddress: nil,
-	}
-	return MakeUniversalRequest[OrderResponse](api, request)
 }
 
 // Bulk modify orders
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#modify-multiple-orders
 func (api *ExchangeAPI) BulkModifyOrders(modifyRequests []ModifyOrderRequest, isSpot bool) (*OrderResponse, error) {
-	wires := []ModifyOrderWire{}
+	action, err := api.bulkModifyOrdersAction(modifyRequests, isSpot)
+	if err != nil {
+		return nil, err
+	}
+	return Execute(api, action)
+}
 
+// Helper function to build the action of BulkModifyOrders
+func (api *ExchangeAPI) bulkModifyOrdersAction(modifyRequests []ModifyOrderRequest, isSpot bool) (ModifyOrderAction, error) {
+	wires := []ModifyOrderWire{}
+	var meta map[string]AssetInfo
+	if isSpot {
+		meta = api.spotMeta
+	} else {
+		meta = api.meta
+	}
+	if api.validateOrders {
+		if err := ValidateModifyOrderRequests(modifyRequests, meta, isSpot); err != nil {
+			api.debug("Error validating orders: %s", err)
+			return ModifyOrderAction{}, err
+		}
+	}
 	for _, req := range modifyRequests {
-		wires = append(wires, ModifyOrderRequestToWire(req, api.meta, isSpot))
+		wires = append(wires, ModifyOrderRequestToWire(req, meta, isSpot))
 	}
-	action := ModifyOrderAction{
+	return ModifyOrderAction{
 		Type:     "batchModify",
 		Modifies: wires,
-	}
-
-	timestamp := GetNonce()
-	vVal, rVal, sVal, signErr := api.SignL1Action(action, timestamp)
-	if signErr != nil {
-		return nil, signErr
-	}
-	request := ExchangeRequest{
-		Action:       action,
-		Nonce:        timestamp,
-		Signature:    ToTypedSig(rVal, sVal, vVal),
-		VaultAddress: nil,
-	}
-	return MakeUniversalRequest[OrderResponse](api, request)
+	}, nil
 }
 
 // Cancel exact order by Client Order Id
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#cancel-order-s-by-cloid
 func (api *ExchangeAPI) CancelOrderByCloid(coin string, clientOID string) (*OrderResponse, error) {
-	timestamp := GetNonce()
 	action := CancelCloidOrderAction{
 		Type: "cancelByCloid",
 		Cancels: []CancelCloidWire{
@@ -220,69 +339,183 @@ func (api *ExchangeAPI) CancelOrderByCloid(coin string, clientOID string) (*Orde
 			},
 		},
 	}
-	v, r, s, err := api.SignL1Action(action, timestamp)
-	if err != nil {
-		api.debug("Error signing L1 action: %s", err)
-		return nil, err
-	}
-	request := ExchangeRequest{
-		Action:       action,
-		Nonce:        timestamp,
-		Signature:    ToTypedSig(r, s, v),
-		VaultAddress: nil,
-	}
-	return MakeUniversalRequest[OrderResponse](api, request)
+	return Execute(api, action)
 }
 
 // Update leverage for a coin
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#update-leverage
 func (api *ExchangeAPI) UpdateLeverage(coin string, isCross bool, leverage int) (*DefaultExchangeResponse, error) {
-	timestamp := GetNonce()
-	action := UpdateLeverageAction{
+	return Execute(api, api.updateLeverageAction(coin, isCross, leverage))
+}
+
+// Helper function to build the action of UpdateLeverage
+func (api *ExchangeAPI) updateLeverageAction(coin string, isCross bool, leverage int) UpdateLeverageAction {
+	return UpdateLeverageAction{
 		Type:     "updateLeverage",
 		Asset:    api.meta[coin].AssetId,
 		IsCross:  isCross,
 		Leverage: leverage,
 	}
-	v, r, s, err := api.SignL1Action(action, timestamp)
-	if err != nil {
-		api.debug("Error signing L1 action: %s", err)
-		return nil, err
-	}
-	request := ExchangeRequest{
-		Action:       action,
-		Nonce:        timestamp,
-		Signature:    ToTypedSig(r, s, v),
-		VaultAddress: nil,
-	}
-	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
 }
 
 // Initiate a withdraw request
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#initiate-a-withdrawal-request
 func (api *ExchangeAPI) Withdraw(destination string, amount float64) (*WithdrawResponse, error) {
-	nonce := GetNonce()
-	action := WithdrawAction{
+	return Execute(api, withdrawAction(destination, amount))
+}
+
+// Helper function to build the action of Withdraw
+func withdrawAction(destination string, amount float64) WithdrawAction {
+	return WithdrawAction{
 		Type:        "withdraw3",
 		Destination: destination,
-		Amount:      SizeToWire(amount, USDC_SZ_DECIMALS),
-		Time:        nonce,
+		Amount:      SizeToWireRounded(amount, USDC_SZ_DECIMALS, RoundFloor),
+	}
+}
+
+// Send USDC to another address on the Hyperliquid L1
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#core-usdc-transfer
+func (api *ExchangeAPI) UsdSend(destination string, amount float64) (*DefaultExchangeResponse, error) {
+	return Execute(api, usdSendAction(destination, amount))
+}
+
+// Helper function to build the action of UsdSend
+func usdSendAction(destination string, amount float64) UsdSendAction {
+	return UsdSendAction{
+		Type:        "usdSend",
+		Destination: destination,
+		Amount:      SizeToWireRounded(amount, USDC_SZ_DECIMALS, RoundFloor),
+	}
+}
+
+// Send a spot token to another address on the Hyperliquid L1.
+// Token is "name:tokenId", e.g. "PURR:0xc4bf3f870c0e9465323c0b6ed28096c2".
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#core-spot-transfer
+func (api *ExchangeAPI) SpotSend(destination string, token string, amount float64) (*DefaultExchangeResponse, error) {
+	return Execute(api, spotSendAction(destination, token, amount))
+}
+
+// Helper function to build the action of SpotSend
+func spotSendAction(destination string, token string, amount float64) SpotSendAction {
+	return SpotSendAction{
+		Type:        "spotSend",
+		Destination: destination,
+		Token:       token,
+		Amount:      NewDecimalFromFloat(amount).String(),
 	}
-	signatureChainID, chainType := api.getChainParams()
-	action.HyperliquidChain = chainType
-	action.SignatureChainID = signatureChainID
-	v, r, s, err := api.SignWithdrawAction(action)
+}
+
+// Approve an agent (API wallet) to sign L1 actions for the account
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/nonces-and-api-wallets
+func (api *ExchangeAPI) ApproveAgent(agentAddress string, agentName string) (*DefaultExchangeResponse, error) {
+	return Execute(api, approveAgentAction(agentAddress, agentName))
+}
+
+// Helper function to build the action of ApproveAgent
+func approveAgentAction(agentAddress string, agentName string) ApproveAgentAction {
+	return ApproveAgentAction{
+		Type:         "approveAgent",
+		AgentAddress: agentAddress,
+		AgentName:    agentName,
+	}
+}
+
+// Convert the account to a multi-sig account.
+// The actions of the account must then be signed by threshold of the authorized users (see MultiSigCollector).
+// https://hyperliquid.gitbook.io/hyperliquid-docs/hypercore/multi-sig
+func (api *ExchangeAPI) ConvertToMultiSigUser(authorizedUsers []string, threshold int) (*DefaultExchangeResponse, error) {
+	action, err := convertToMultiSigUserAction(authorizedUsers, threshold)
 	if err != nil {
-		api.debug("Error signing withdraw action: %s", err)
 		return nil, err
 	}
-	request := &ExchangeRequest{
-		Action:       action,
-		Nonce:        nonce,
-		Signature:    ToTypedSig(r, s, v),
-		VaultAddress: nil,
+	return Execute(api, action)
+}
+
+// Helper function to build the action of ConvertToMultiSigUser
+func convertToMultiSigUserAction(authorizedUsers []string, threshold int) (ConvertToMultiSigUserAction, error) {
+	if threshold < 1 || threshold > len(authorizedUsers) {
+		return ConvertToMultiSigUserAction{}, APIError{Message: fmt.Sprintf("Invalid threshold %d for %d authorized users", threshold, len(authorizedUsers))}
+	}
+	users := make([]string, len(authorizedUsers))
+	for i, user := range authorizedUsers {
+		if !common.IsHexAddress(user) {
+			return ConvertToMultiSigUserAction{}, APIError{Message: fmt.Sprintf("Invalid authorized user: %s", user)}
+		}
+		users[i] = strings.ToLower(user)
+	}
+	sort.Strings(users)
+	signers, err := json.Marshal(MultiSigSigners{AuthorizedUsers: users, Threshold: threshold})
+	if err != nil {
+		return ConvertToMultiSigUserAction{}, err
 	}
-	return MakeUniversalRequest[WithdrawResponse](api, request)
+	return ConvertToMultiSigUserAction{
+		Type:    "convertToMultiSigUser",
+		Signers: string(signers),
+	}, nil
+}
+
+//
+// Prepare Methods
+//
+// Same as the base methods, but the action is returned unsigned with its EIP-712 typed data,
+// to be signed by an external wallet and sent with SubmitSigned.
+//
+
+// PrepareBulkOrders prepares the action of BulkOrders
+func (api *ExchangeAPI) PrepareBulkOrders(requests []OrderRequest, grouping Grouping, isSpot bool) (*UnsignedAction, error) {
+	action, err := api.bulkOrdersAction(requests, grouping, isSpot)
+	if err != nil {
+		return nil, err
+	}
+	return api.PrepareAction(action)
+}
+
+// PrepareBulkCancelOrders prepares the action of BulkCancelOrders
+func (api *ExchangeAPI) PrepareBulkCancelOrders(cancels []CancelOidWire) (*UnsignedAction, error) {
+	return api.PrepareAction(bulkCancelOrdersAction(cancels))
+}
+
+// PrepareBulkModifyOrders prepares the action of BulkModifyOrders
+func (api *ExchangeAPI) PrepareBulkModifyOrders(modifyRequests []ModifyOrderRequest, isSpot bool) (*UnsignedAction, error) {
+	action, err := api.bulkModifyOrdersAction(modifyRequests, isSpot)
+	if err != nil {
+		return nil, err
+	}
+	return api.PrepareAction(action)
+}
+
+// PrepareUpdateLeverage prepares the action of UpdateLeverage
+func (api *ExchangeAPI) PrepareUpdateLeverage(coin string, isCross bool, leverage int) (*UnsignedAction, error) {
+	return api.PrepareAction(api.updateLeverageAction(coin, isCross, leverage))
+}
+
+// PrepareWithdraw prepares the action of Withdraw
+func (api *ExchangeAPI) PrepareWithdraw(destination string, amount float64) (*UnsignedAction, error) {
+	return api.PrepareAction(withdrawAction(destination, amount))
+}
+
+// PrepareUsdSend prepares the action of UsdSend
+func (api *ExchangeAPI) PrepareUsdSend(destination string, amount float64) (*UnsignedAction, error) {
+	return api.PrepareAction(usdSendAction(destination, amount))
+}
+
+// PrepareSpotSend prepares the action of SpotSend
+func (api *ExchangeAPI) PrepareSpotSend(destination string, token string, amount float64) (*UnsignedAction, error) {
+	return api.PrepareAction(spotSendAction(destination, token, amount))
+}
+
+// PrepareConvertToMultiSigUser prepares the action of ConvertToMultiSigUser
+func (api *ExchangeAPI) PrepareConvertToMultiSigUser(authorizedUsers []string, threshold int) (*UnsignedAction, error) {
+	action, err := convertToMultiSigUserAction(authorizedUsers, threshold)
+	if err != nil {
+		return nil, err
+	}
+	return api.PrepareAction(action)
+}
+
+// PrepareApproveAgent prepares the action of ApproveAgent
+func (api *ExchangeAPI) PrepareApproveAgent(agentAddress string, agentName string) (*UnsignedAction, error) {
+	return api.PrepareAction(approveAgentAction(agentAddress, agentName))
 }
 
 //
@@ -297,6 +530,7 @@ func (api *ExchangeAPI) Order(request OrderRequest, grouping Grouping) (*OrderRe
 // Open a market order.
 // Limit order with TIF=IOC and px=market price * (1 +- slippage).
 // Size determines the amount of the coin to buy/sell.
+// The size is capped to the available size if SetCapOrderSize(true) was called.
 //
 //	MarketOrder("BTC", 0.1, nil) // Buy 0.1 BTC
 //	MarketOrder("BTC", -0.1, nil) // Sell 0.1 BTC
@@ -304,6 +538,13 @@ func (api *ExchangeAPI) Order(request OrderRequest, grouping Grouping) (*OrderRe
 func (api *ExchangeAPI) MarketOrder(coin string, size float64, slippage *float64, clientOID ...string) (*OrderResponse, error) {
 	slpg := GetSlippage(slippage)
 	isBuy := IsBuy(size)
+	sz := NewDecimalFromFloat(math.Abs(size))
+	if api.capOrderSize {
+		var err error
+		if sz, err = api.capSize(coin, isBuy, sz); err != nil {
+			return nil, err
+		}
+	}
 	finalPx := api.SlippagePrice(coin, isBuy, slpg)
 	orderType := OrderType{
 		Limit: &LimitOrderType{
@@ -313,10 +554,12 @@ func (api *ExchangeAPI) MarketOrder(coin string, size float64, slippage *float64
 	orderRequest := OrderRequest{
 		Coin:       coin,
 		IsBuy:      isBuy,
-		Sz:         math.Abs(size),
-		LimitPx:    finalPx,
+		Sz:         sz,
+		LimitPx:    NewDecimalFromFloat(finalPx),
 		OrderType:  orderType,
 		ReduceOnly: false,
+		PxRounding: api.pxRounding,
+		SzRounding: api.szRounding,
 	}
 	if len(clientOID) > 0 {
 		orderRequest.Cloid = clientOID[0]
@@ -344,10 +587,12 @@ func (api *ExchangeAPI) MarketOrderSpot(coin string, size float64, slippage *flo
 	orderRequest := OrderRequest{
 		Coin:       coin,
 		IsBuy:      isBuy,
-		Sz:         math.Abs(size),
-		LimitPx:    finalPx,
+		Sz:         NewDecimalFromFloat(math.Abs(size)),
+		LimitPx:    NewDecimalFromFloat(finalPx),
 		OrderType:  orderType,
 		ReduceOnly: false,
+		PxRounding: api.pxRounding,
+		SzRounding: api.szRounding,
 	}
 	return api.OrderSpot(orderRequest, GroupingNa)
 }
@@ -355,12 +600,21 @@ func (api *ExchangeAPI) MarketOrderSpot(coin string, size float64, slippage *flo
 // Open a limit order.
 // Order type can be Gtc, Ioc, Alo.
 // Size determines the amount of the coin to buy/sell.
+// The size is capped to the available size if SetCapOrderSize(true) was called.
 // See the constants TifGtc, TifIoc, TifAlo.
 func (api *ExchangeAPI) LimitOrder(orderType string, coin string, size float64, px float64, reduceOnly bool, clientOID ...string) (*OrderResponse, error) {
 	// check if the order type is valid
 	if orderType != TifGtc && orderType != TifIoc && orderType != TifAlo {
 		return nil, APIError{Message: fmt.Sprintf("Invalid order type: %s. Available types: %s, %s, %s", orderType, TifGtc, TifIoc, TifAlo)}
 	}
+	isBuy := IsBuy(size)
+	sz := NewDecimalFromFloat(math.Abs(size))
+	if api.capOrderSize && !reduceOnly {
+		var err error
+		if sz, err = api.capSize(coin, isBuy, sz); err != nil {
+			return nil, err
+		}
+	}
 	orderTypeZ := OrderType{
 		Limit: &LimitOrderType{
 			Tif: orderType,
@@ -368,11 +622,13 @@ func (api *ExchangeAPI) LimitOrder(orderType string, coin string, size float64,
 	}
 	orderRequest := OrderRequest{
 		Coin:       coin,
-		IsBuy:      IsBuy(size),
-		Sz:         math.Abs(size),
-		LimitPx:    px,
+		IsBuy:      isBuy,
+		Sz:         sz,
+		LimitPx:    NewDecimalFromFloat(px),
 		OrderType:  orderTypeZ,
 		ReduceOnly: reduceOnly,
+		PxRounding: api.pxRounding,
+		SzRounding: api.szRounding,
 	}
 	if len(clientOID) > 0 {
 		orderRequest.Cloid = clientOID[0]
@@ -400,7 +656,7 @@ func (api *ExchangeAPI) ClosePosition(coin string) (*OrderResponse, error) {
 		}
 		size := item.Szi
 		// reverse the position to close
-		isBuy := !IsBuy(size)
+		isBuy := !size.IsPositive()
 		finalPx := api.SlippagePrice(coin, isBuy, slippage)
 		orderType := OrderType{
 			Limit: &LimitOrderType{
@@ -410,10 +666,12 @@ func (api *ExchangeAPI) ClosePosition(coin string) (*OrderResponse, error) {
 		orderRequest := OrderRequest{
 			Coin:       coin,
 			IsBuy:      isBuy,
-			Sz:         math.Abs(size),
-			LimitPx:    finalPx,
+			Sz:         size.Abs(),
+			LimitPx:    NewDecimalFromFloat(finalPx),
 			OrderType:  orderType,
 			ReduceOnly: true,
+			PxRounding: api.pxRounding,
+			SzRounding: api.szRounding,
 		}
 		return api.Order(orderRequest, GroupingNa)
 	}
@@ -466,6 +724,7 @@ func (api *ExchangeAPI) CancelAllOrders() (*OrderResponse, error) {
 
 // CreateUnsignedOrder creates an unsigned order request
 // Similar to MarketOrder and LimitOrder, but returns the unsigned request instead of sending it
+// See SigningEnvelope to sign any action on an offline host.
 func (api *ExchangeAPI) CreateUnsignedOrder(coin string, size float64, price float64, orderType string, reduceOnly bool, isSpot bool) (*ExchangeRequest, error) {
 	// 构建订单类型
 	var orderTypeObj OrderType
@@ -483,10 +742,12 @@ func (api *ExchangeAPI) CreateUnsignedOrder(coin string, size float64, price flo
 	request := OrderRequest{
 		Coin:       coin,
 		IsBuy:      IsBuy(size),
-		Sz:         math.Abs(size),
-		LimitPx:    price,
+		Sz:         NewDecimalFromFloat(math.Abs(size)),
+		LimitPx:    NewDecimalFromFloat(price),
 		OrderType:  orderTypeObj,
 		ReduceOnly: reduceOnly,
+		PxRounding: api.pxRounding,
+		SzRounding: api.szRounding,
 	}
 
 	// 转换为订单线
@@ -500,13 +761,16 @@ func (api *ExchangeAPI) CreateUnsignedOrder(coin string, size float64, price flo
 	wires = append(wires, OrderRequestToWire(request, meta, isSpot))
 
 	// 创建订单动作
-	timestamp := GetNonce()
+	timestamp, err := api.nextNonce()
+	if err != nil {
+		return nil, err
+	}
 	action := OrderWiresToOrderAction(wires, GroupingNa)
 
 	return &ExchangeRequest{
 		Action:       action,
 		Nonce:        timestamp,
-		VaultAddress: nil,
+		VaultAddress: api.getVaultAddress(),
 	}, nil
 }
 
@@ -548,10 +812,12 @@ func (api *ExchangeAPI) CreateUnsignedMarketOrder(coin string, size float64, sli
 	request := OrderRequest{
 		Coin:       coin,
 		IsBuy:      isBuy,
-		Sz:         math.Abs(size),
-		LimitPx:    finalPx,
+		Sz:         NewDecimalFromFloat(math.Abs(size)),
+		LimitPx:    NewDecimalFromFloat(finalPx),
 		OrderType:  orderTypeObj,
 		ReduceOnly: false,
+		PxRounding: api.pxRounding,
+		SzRounding: api.szRounding,
 	}
 
 	// 转换为订单线
@@ -565,13 +831,16 @@ func (api *ExchangeAPI) CreateUnsignedMarketOrder(coin string, size float64, sli
 	wires = append(wires, OrderRequestToWire(request, meta, isSpot))
 
 	// 创建订单动作
-	timestamp := GetNonce()
+	timestamp, err := api.nextNonce()
+	if err != nil {
+		return nil, err
+	}
 	action := OrderWiresToOrderAction(wires, GroupingNa)
 
 	return &ExchangeRequest{
 		Action:       action,
 		Nonce:        timestamp,
-		VaultAddress: nil,
+		VaultAddress: api.getVaultAddress(),
 	}, nil
 }
 
@@ -593,10 +862,12 @@ func (api *ExchangeAPI) CreateUnsignedLimitOrder(coin string, size float64, pric
 	request := OrderRequest{
 		Coin:       coin,
 		IsBuy:      IsBuy(size),
-		Sz:         math.Abs(size),
-		LimitPx:    price,
+		Sz:         NewDecimalFromFloat(math.Abs(size)),
+		LimitPx:    NewDecimalFromFloat(price),
 		OrderType:  orderTypeObj,
 		ReduceOnly: reduceOnly,
+		PxRounding: api.pxRounding,
+		SzRounding: api.szRounding,
 	}
 
 	// 转换为订单线
@@ -610,12 +881,15 @@ func (api *ExchangeAPI) CreateUnsignedLimitOrder(coin string, size float64, pric
 	wires = append(wires, OrderRequestToWire(request, meta, isSpot))
 
 	// 创建订单动作
-	timestamp := GetNonce()
+	timestamp, err := api.nextNonce()
+	if err != nil {
+		return nil, err
+	}
 	action := OrderWiresToOrderAction(wires, GroupingNa)
 
 	return &ExchangeRequest{
 		Action:       action,
 		Nonce:        timestamp,
-		VaultAddress: nil,
+		VaultAddress: api.getVaultAddress(),
 	}, nil
 }
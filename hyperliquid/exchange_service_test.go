@@ -15,8 +15,8 @@ func TestOrderFlow(t *testing.T) {
 		{
 			Coin:    "SOL",
 			IsBuy:   true,
-			Sz:      0.1,
-			LimitPx: 100,
+			Sz:      MustDecimal("0.1"),
+			LimitPx: MustDecimal("100"),
 			OrderType: OrderType{
 				Limit: &LimitOrderType{
 					Tif: "Gtc",
@@ -27,7 +27,7 @@ func TestOrderFlow(t *testing.T) {
 	}
 
 	// 2. 创建未签名交易
-	unsignedRequest, err := api.CreateUnsignedOrder(requests, "na", false)
+	unsignedRequest, err := api.CreateUnsignedOrder(requests[0].Coin, requests[0].Sz.InexactFloat64(), requests[0].LimitPx.InexactFloat64(), requests[0].OrderType.Limit.Tif, requests[0].ReduceOnly, false)
 	if err != nil {
 		t.Fatalf("创建未签名交易失败: %v", err)
 	}
@@ -58,8 +58,8 @@ func TestOrderFlowWithModification(t *testing.T) {
 		{
 			Coin:    "BTC",
 			IsBuy:   true,
-			Sz:      0.01,
-			LimitPx: 50000,
+			Sz:      MustDecimal("0.01"),
+			LimitPx: MustDecimal("50000"),
 			OrderType: OrderType{
 				Limit: &LimitOrderType{
 					Tif: "Gtc",
@@ -70,7 +70,7 @@ func TestOrderFlowWithModification(t *testing.T) {
 	}
 
 	// 2. 创建未签名交易
-	unsignedRequest, err := api.CreateUnsignedOrder(requests, "na", false)
+	unsignedRequest, err := api.CreateUnsignedOrder(requests[0].Coin, requests[0].Sz.InexactFloat64(), requests[0].LimitPx.InexactFloat64(), requests[0].OrderType.Limit.Tif, requests[0].ReduceOnly, false)
 	if err != nil {
 		t.Fatalf("创建未签名交易失败: %v", err)
 	}
@@ -109,8 +109,8 @@ func TestOrderFlowWithErrorHandling(t *testing.T) {
 		{
 			Coin:    "ETH",
 			IsBuy:   true,
-			Sz:      0,
-			LimitPx: 3000,
+			Sz:      MustDecimal("0"),
+			LimitPx: MustDecimal("3000"),
 			OrderType: OrderType{
 				Limit: &LimitOrderType{
 					Tif: "Gtc",
@@ -121,7 +121,7 @@ func TestOrderFlowWithErrorHandling(t *testing.T) {
 	}
 
 	// 2. 创建未签名交易
-	unsignedRequest, err := api.CreateUnsignedOrder(requests, "na", false)
+	unsignedRequest, err := api.CreateUnsignedOrder(requests[0].Coin, requests[0].Sz.InexactFloat64(), requests[0].LimitPx.InexactFloat64(), requests[0].OrderType.Limit.Tif, requests[0].ReduceOnly, false)
 	if err != nil {
 		t.Fatalf("创建未签名交易失败: %v", err)
 	}
@@ -1,12 +1,13 @@
 package hyperliquid
 
 import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
 
 func (api *ExchangeAPI) Sign(request *SignRequest) (byte, [32]byte, [32]byte, error) {
-	signer := NewSigner(api.keyManager)
-	v, r, s, err := signer.Sign(request)
+	v, r, s, err := SignTypedData(api.signer, request)
 	if err != nil {
 		api.debug("Error SignInner: %s", err)
 		return 0, [32]byte{}, [32]byte{}, err
@@ -15,22 +16,33 @@ func (api *ExchangeAPI) Sign(request *SignRequest) (byte, [32]byte, [32]byte, er
 }
 
 func (api *ExchangeAPI) SignUserSignableAction(action any, payloadTypes []apitypes.Type, primaryType string) (byte, [32]byte, [32]byte, error) {
-	message, err := StructToMap(action)
+	srequest, err := api.BuildUserSignedEIP712Message(action, payloadTypes, primaryType)
 	if err != nil {
 		return 0, [32]byte{}, [32]byte{}, err
 	}
+	return api.Sign(srequest)
+}
+
+// BuildUserSignedEIP712Message builds the typed data of an action signed by the user.
+// The nonce and network fields must already be set in the action.
+func (api *ExchangeAPI) BuildUserSignedEIP712Message(action any, payloadTypes []apitypes.Type, primaryType string) (*SignRequest, error) {
+	message, err := StructToMap(action)
+	if err != nil {
+		return nil, err
+	}
+	signatureChainId, _ := message["signatureChainId"].(string)
 	// Remove unnecessary fields for signing
 	delete(message, "type")
 	delete(message, "signatureChainId")
 
-	signRequest := &SignRequest{
-		DomainName:  "HyperliquidSignTransaction",
-		PrimaryType: primaryType,
-		DType:       payloadTypes,
-		DTypeMsg:    message,
-		IsMainNet:   api.IsMainnet(),
-	}
-	return api.Sign(signRequest)
+	return &SignRequest{
+		DomainName:       "HyperliquidSignTransaction",
+		PrimaryType:      primaryType,
+		DType:            payloadTypes,
+		DTypeMsg:         message,
+		IsMainNet:        api.IsMainnet(),
+		SignatureChainId: signatureChainId,
+	}, nil
 }
 
 func (api *ExchangeAPI) SignL1Action(action any, timestamp uint64) (byte, [32]byte, [32]byte, error) {
@@ -43,7 +55,7 @@ func (api *ExchangeAPI) SignL1Action(action any, timestamp uint64) (byte, [32]by
 }
 
 func (api *ExchangeAPI) BuildEIP712Message(action any, timestamp uint64) (*SignRequest, error) {
-	hash, err := buildActionHash(action, "", timestamp)
+	hash, err := buildActionHash(action, api.vaultAddress, timestamp)
 	if err != nil {
 		return nil, err
 	}
@@ -68,23 +80,124 @@ func (api *ExchangeAPI) BuildEIP712Message(action any, timestamp uint64) (*SignR
 }
 
 func (api *ExchangeAPI) SignWithdrawAction(action WithdrawAction) (byte, [32]byte, [32]byte, error) {
+	userSigned := userSignedActionTypes["withdraw3"]
+	return api.SignUserSignableAction(action, userSigned.Types, userSigned.PrimaryType)
+}
+
+// EIP-712 primary type and fields of an action signed by the user (not by an agent)
+type userSignedActionType struct {
+	PrimaryType string
+	Types       []apitypes.Type
+}
+
+// forMultiSig returns the types signed by an authorized user of a multi-sig account:
+// the multi-sig user and the outer signer are added after the network.
+func (userSigned userSignedActionType) forMultiSig() userSignedActionType {
 	types := []apitypes.Type{
-		{
-			Name: "hyperliquidChain",
-			Type: "string",
+		userSigned.Types[0],
+		{Name: "payloadMultiSigUser", Type: "address"},
+		{Name: "outerSigner", Type: "address"},
+	}
+	return userSignedActionType{
+		PrimaryType: userSigned.PrimaryType,
+		Types:       append(types, userSigned.Types[1:]...),
+	}
+}
+
+// buildMultiSigSignRequest builds the typed data signed by the outer signer of a multiSig action
+func buildMultiSigSignRequest(multiSigActionHash common.Hash, signatureChainId string, nonce uint64, isMainnet bool) *SignRequest {
+	return &SignRequest{
+		DomainName:  "HyperliquidSignTransaction",
+		PrimaryType: "HyperliquidTransaction:SendMultiSig",
+		DType: []apitypes.Type{
+			{Name: "hyperliquidChain", Type: "string"},
+			{Name: "multiSigActionHash", Type: "bytes32"},
+			{Name: "nonce", Type: "uint64"},
 		},
-		{
-			Name: "destination",
-			Type: "string",
+		DTypeMsg: map[string]any{
+			"hyperliquidChain":   getNetworkName(isMainnet),
+			"multiSigActionHash": hexutil.Bytes(multiSigActionHash.Bytes()),
+			"nonce":              float64(nonce),
 		},
-		{
-			Name: "amount",
-			Type: "string",
+		IsMainNet:        isMainnet,
+		SignatureChainId: signatureChainId,
+	}
+}
+
+// EIP-712 types of the user signed actions by action type.
+// https://github.com/hyperliquid-dex/hyperliquid-python-sdk/blob/master/hyperliquid/utils/signing.py
+var userSignedActionTypes = map[string]userSignedActionType{
+	"withdraw3": {
+		PrimaryType: "HyperliquidTransaction:Withdraw",
+		Types: []apitypes.Type{
+			{Name: "hyperliquidChain", Type: "string"},
+			{Name: "destination", Type: "string"},
+			{Name: "amount", Type: "string"},
+			{Name: "time", Type: "uint64"},
 		},
-		{
-			Name: "time",
-			Type: "uint64",
+	},
+	"usdSend": {
+		PrimaryType: "HyperliquidTransaction:UsdSend",
+		Types: []apitypes.Type{
+			{Name: "hyperliquidChain", Type: "string"},
+			{Name: "destination", Type: "string"},
+			{Name: "amount", Type: "string"},
+			{Name: "time", Type: "uint64"},
 		},
-	}
-	return api.SignUserSignableAction(action, types, "HyperliquidTransaction:Withdraw")
+	},
+	"spotSend": {
+		PrimaryType: "HyperliquidTransaction:SpotSend",
+		Types: []apitypes.Type{
+			{Name: "hyperliquidChain", Type: "string"},
+			{Name: "destination", Type: "string"},
+			{Name: "token", Type: "string"},
+			{Name: "amount", Type: "string"},
+			{Name: "time", Type: "uint64"},
+		},
+	},
+	"usdClassTransfer": {
+		PrimaryType: "HyperliquidTransaction:UsdClassTransfer",
+		Types: []apitypes.Type{
+			{Name: "hyperliquidChain", Type: "string"},
+			{Name: "amount", Type: "string"},
+			{Name: "toPerp", Type: "bool"},
+			{Name: "nonce", Type: "uint64"},
+		},
+	},
+	"approveAgent": {
+		PrimaryType: "HyperliquidTransaction:ApproveAgent",
+		Types: []apitypes.Type{
+			{Name: "hyperliquidChain", Type: "string"},
+			{Name: "agentAddress", Type: "address"},
+			{Name: "agentName", Type: "string"},
+			{Name: "nonce", Type: "uint64"},
+		},
+	},
+	"approveBuilderFee": {
+		PrimaryType: "HyperliquidTransaction:ApproveBuilderFee",
+		Types: []apitypes.Type{
+			{Name: "hyperliquidChain", Type: "string"},
+			{Name: "maxFeeRate", Type: "string"},
+			{Name: "builder", Type: "address"},
+			{Name: "nonce", Type: "uint64"},
+		},
+	},
+	"convertToMultiSigUser": {
+		PrimaryType: "HyperliquidTransaction:ConvertToMultiSigUser",
+		Types: []apitypes.Type{
+			{Name: "hyperliquidChain", Type: "string"},
+			{Name: "signers", Type: "string"},
+			{Name: "nonce", Type: "uint64"},
+		},
+	},
+	"tokenDelegate": {
+		PrimaryType: "HyperliquidTransaction:TokenDelegate",
+		Types: []apitypes.Type{
+			{Name: "hyperliquidChain", Type: "string"},
+			{Name: "validator", Type: "address"},
+			{Name: "wei", Type: "uint64"},
+			{Name: "isUndelegate", Type: "bool"},
+			{Name: "nonce", Type: "uint64"},
+		},
+	},
 }
@@ -1,11 +1,19 @@
 package hyperliquid
 
 import (
+	"encoding/json"
+	"errors"
 	"log"
 	"math"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
 
 func GetExchangeAPI() *ExchangeAPI {
@@ -23,6 +31,207 @@ func GetExchangeAPI() *ExchangeAPI {
 	return exchangeAPI
 }
 
+// GetMockExchangeAPI returns an ExchangeAPI signing with testPrivateKey and sending the requests to handler
+func GetMockExchangeAPI(t *testing.T, handler func(request ExchangeRequest) any) *ExchangeAPI {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request ExchangeRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("Decode() error = %v", err)
+		}
+		json.NewEncoder(w).Encode(handler(request))
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient(false)
+	client.baseUrl = server.URL
+	// Nonces and clock offset of the mock server must not leak into the process-wide manager
+	client.SetNonceManager(NewNonceManager(NewMemoryNonceStore()))
+	if GLOBAL_DEBUG {
+		client.SetDebugActive()
+	}
+	if err := client.SetPrivateKey(testPrivateKey); err != nil {
+		t.Fatal(err)
+	}
+	return &ExchangeAPI{
+		Client:       *client,
+		baseEndpoint: "/exchange",
+		meta:         map[string]AssetInfo{"ETH": {SzDecimals: 4, AssetId: 1}},
+	}
+}
+
+// Helper function to get a mock exchange API which checks that every request is signed by signer.
+// It returns the type of the last action received.
+func getVerifyingExchangeAPI(t *testing.T, signer common.Address) (*ExchangeAPI, *string) {
+	var actionType string
+	api := GetMockExchangeAPI(t, func(request ExchangeRequest) any {
+		var action map[string]any
+		json.Unmarshal(request.Action.(json.RawMessage), &action)
+		actionType, _ = action["type"].(string)
+		if err := VerifySigner(request, false, signer); err != nil {
+			t.Errorf("VerifySigner(%s) error = %v", actionType, err)
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	return api, &actionType
+}
+
+func TestExchangeAPI_Execute(t *testing.T) {
+	vault := "0x0000000000000000000000000000000000000001"
+	var actionType string
+	var api *ExchangeAPI
+	api = GetMockExchangeAPI(t, func(request ExchangeRequest) any {
+		var action map[string]any
+		json.Unmarshal(request.Action.(json.RawMessage), &action)
+		actionType = action["type"].(string)
+		if err := VerifySigner(request, false, api.Signer().Address()); err != nil {
+			t.Errorf("VerifySigner(%s) error = %v", actionType, err)
+		}
+		if actionType == "withdraw3" {
+			if request.VaultAddress != nil {
+				t.Errorf("withdraw3 vaultAddress = %v, want nil", *request.VaultAddress)
+			}
+			if action["time"].(float64) != float64(request.Nonce) || action["hyperliquidChain"] != "Testnet" {
+				t.Errorf("withdraw3 action = %v, want time %d on Testnet", action, request.Nonce)
+			}
+			return map[string]any{"status": "ok"}
+		}
+		if request.VaultAddress == nil || *request.VaultAddress != vault {
+			t.Errorf("%s vaultAddress = %v, want %s", actionType, request.VaultAddress, vault)
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	api.SetVaultAddress(vault)
+
+	res, err := api.UpdateLeverage("ETH", true, 10)
+	if err != nil {
+		t.Fatalf("UpdateLeverage() error = %v", err)
+	}
+	if actionType != "updateLeverage" || res.Status != "ok" || res.Response.Type != "default" {
+		t.Errorf("UpdateLeverage() = %+v for %s", res, actionType)
+	}
+
+	withdraw, err := api.Withdraw("0x0000000000000000000000000000000000000002", 10.129)
+	if err != nil {
+		t.Fatalf("Withdraw() error = %v", err)
+	}
+	if actionType != "withdraw3" || withdraw.Status != "ok" {
+		t.Errorf("Withdraw() = %+v for %s", withdraw, actionType)
+	}
+}
+
+func TestExchangeAPI_SubmitSigned(t *testing.T) {
+	// The wallet signs outside of the client, which has no signer
+	wallet, _ := NewPKeyManager(testPrivateKey)
+	api, actionType := getVerifyingExchangeAPI(t, wallet.Address())
+	api.signer = nil
+	api.SetAccountAddress(wallet.Address().Hex())
+
+	prepare := map[string]func() (*UnsignedAction, error){
+		"cancel": func() (*UnsignedAction, error) {
+			return api.PrepareBulkCancelOrders([]CancelOidWire{{Asset: 1, Oid: 123}})
+		},
+		"batchModify": func() (*UnsignedAction, error) {
+			orderType := OrderType{Limit: &LimitOrderType{Tif: TifGtc}}
+			return api.PrepareBulkModifyOrders([]ModifyOrderRequest{{
+				OrderId: 123, Coin: "ETH", IsBuy: true, Sz: NewDecimalFromFloat(0.1), LimitPx: NewDecimalFromFloat(2500), OrderType: orderType,
+			}}, false)
+		},
+		"updateLeverage": func() (*UnsignedAction, error) { return api.PrepareUpdateLeverage("ETH", true, 10) },
+		"withdraw3": func() (*UnsignedAction, error) {
+			return api.PrepareWithdraw("0x0000000000000000000000000000000000000002", 10)
+		},
+		"usdSend": func() (*UnsignedAction, error) {
+			return api.PrepareUsdSend("0x0000000000000000000000000000000000000002", 10)
+		},
+		"spotSend": func() (*UnsignedAction, error) {
+			return api.PrepareSpotSend("0x0000000000000000000000000000000000000002", "PURR:0xc4bf3f870c0e9465323c0b6ed28096c2", 1.5)
+		},
+		"approveAgent": func() (*UnsignedAction, error) {
+			return api.PrepareApproveAgent("0x0000000000000000000000000000000000000003", "bot")
+		},
+	}
+	for name, fn := range prepare {
+		t.Run(name, func(t *testing.T) {
+			unsigned, err := fn()
+			if err != nil {
+				t.Fatalf("Prepare() error = %v", err)
+			}
+			// The wallet receives the eth_signTypedData_v4 JSON and signs its hash with V in 27/28
+			data, err := unsigned.TypedDataJSON()
+			if err != nil {
+				t.Fatalf("TypedDataJSON() error = %v", err)
+			}
+			var typedData apitypes.TypedData
+			if err := json.Unmarshal(data, &typedData); err != nil {
+				t.Fatalf("invalid typed data JSON %s: %v", data, err)
+			}
+			hash, _, err := apitypes.TypedDataAndHash(typedData)
+			if err != nil {
+				t.Fatalf("TypedDataAndHash() error = %v", err)
+			}
+			digest, _ := unsigned.Digest()
+			if [32]byte(hash) != digest {
+				t.Errorf("hash of the JSON = %x, want %x", hash, digest)
+			}
+			signature, _ := wallet.SignDigest([32]byte(hash))
+			signature[64] += 27
+
+			res, err := SubmitSigned[DefaultExchangeResponse](api, unsigned, hexutil.Encode(signature))
+			if err != nil {
+				t.Fatalf("SubmitSigned() error = %v", err)
+			}
+			if *actionType != name || res.Status != "ok" {
+				t.Errorf("SubmitSigned() = %+v for %s", res, *actionType)
+			}
+		})
+	}
+
+	unsigned, _ := api.PrepareUsdSend("0x0000000000000000000000000000000000000002", 10)
+	digest, _ := unsigned.Digest()
+	other, _ := NewPKeyManager("1111111111111111111111111111111111111111111111111111111111111111")
+	signature, _ := other.SignDigest(digest)
+	if _, err := SubmitSigned[DefaultExchangeResponse](api, unsigned, hexutil.Encode(signature)); !errors.Is(err, ErrSignerMismatch) {
+		t.Errorf("SubmitSigned(other wallet) error = %v, want ErrSignerMismatch", err)
+	}
+	if _, err := SubmitSigned[DefaultExchangeResponse](api, unsigned, "0x1234"); err == nil {
+		t.Errorf("SubmitSigned(invalid signature) error = nil, want error")
+	}
+}
+
+func TestExchangeAPI_CapSize(t *testing.T) {
+	infoAPI := GetMockInfoAPI(t, func(request InfoRequest) any {
+		if request.Typez != "activeAssetData" || request.Coin != "ETH" {
+			t.Errorf("request = %+v, want activeAssetData for ETH", request)
+		}
+		return json.RawMessage(`{"user":"0x0","coin":"ETH","leverage":{"type":"cross","value":20},
+			"maxTradeSzs":["1.23456","0.5"],"availableToTrade":["1000.0","1000.0"],"markPx":"3000.0"}`)
+	})
+	exchangeAPI := &ExchangeAPI{
+		infoAPI: infoAPI,
+		meta:    map[string]AssetInfo{"ETH": {SzDecimals: 4}},
+	}
+	testCases := []struct {
+		isBuy    bool
+		size     string
+		expected string
+	}{
+		{true, "1", "1"},
+		{true, "2", "1.2345"},
+		{false, "0.7", "0.5"},
+	}
+	for _, tc := range testCases {
+		res, err := exchangeAPI.capSize("ETH", tc.isBuy, MustDecimal(tc.size))
+		if err != nil {
+			t.Errorf("capSize() error = %v", err)
+		}
+		if !res.Equal(MustDecimal(tc.expected)) {
+			t.Errorf("capSize(%v, %v) = %v, want %v", tc.isBuy, tc.size, res, tc.expected)
+		}
+	}
+	if _, err := exchangeAPI.capSize("DOGE", true, MustDecimal("1")); err == nil {
+		t.Errorf("capSize(DOGE) error = nil, want unknown coin error")
+	}
+}
+
 func TestExchangeAPI_Endpoint(t *testing.T) {
 	exchangeAPI := GetExchangeAPI()
 	res := exchangeAPI.Endpoint()
@@ -86,11 +295,11 @@ func TestExchangeAPI_MarketOpen(t *testing.T) {
 	}
 	t.Logf("MakeOpen() = %v", res)
 	avgPrice := res.Response.Data.Statuses[0].Filled.AvgPx
-	if avgPrice == 0 {
+	if avgPrice.IsZero() {
 		t.Errorf("res.Response.Data.Statuses[0].Filled.AvgPx = %v", avgPrice)
 	}
 	totalSize := res.Response.Data.Statuses[0].Filled.TotalSz
-	if totalSize != math.Abs(size) {
+	if !totalSize.Equal(NewDecimalFromFloat(math.Abs(size))) {
 		t.Errorf("res.Response.Data.Statuses[0].Filled.TotalSz = %v", totalSize)
 	}
 	time.Sleep(2 * time.Second) // wait to execute order
@@ -104,7 +313,7 @@ func TestExchangeAPI_MarketOpen(t *testing.T) {
 		if position.Position.Coin == coin {
 			positionOpened = true
 		}
-		if position.Position.Coin == coin && position.Position.Szi == size {
+		if position.Position.Coin == coin && position.Position.Szi.Equal(NewDecimalFromFloat(size)) {
 			positionCorrect = true
 		}
 	}
@@ -168,7 +377,7 @@ func TestExchangeAPI_CreateLimitOrderAndCancelOrderByCloidt(t *testing.T) {
 	var orderCloid string
 	for _, order := range *openOrders {
 		t.Logf("Order: %+v", order)
-		if order.Coin == coin && order.Sz == -size && order.LimitPx == px {
+		if order.Coin == coin && order.Sz.Equal(NewDecimalFromFloat(-size)) && order.LimitPx.Equal(NewDecimalFromFloat(px)) {
 			orderOpened = true
 			orderCloid = order.Cloid
 			break
@@ -204,7 +413,7 @@ func TestExchangeAPI_CreateLimitOrderAndCancelOrderByOid(t *testing.T) {
 	var orderOid int64
 	for _, order := range *openOrders {
 		t.Logf("Order: %+v", order)
-		if order.Coin == coin && order.Sz == size && order.LimitPx == px {
+		if order.Coin == coin && order.Sz.Equal(NewDecimalFromFloat(size)) && order.LimitPx.Equal(NewDecimalFromFloat(px)) {
 			orderOpened = true
 			orderOid = order.Oid
 			break
@@ -238,7 +447,7 @@ func TestExchangeAPI_TestModifyOrder(t *testing.T) {
 	t.Logf("GetAccountOpenOrders() = %v", openOrders)
 	orderOpened := false
 	for _, order := range *openOrders {
-		if order.Coin == coin && order.Sz == size && order.LimitPx == px {
+		if order.Coin == coin && order.Sz.Equal(NewDecimalFromFloat(size)) && order.LimitPx.Equal(NewDecimalFromFloat(px)) {
 			orderOpened = true
 			break
 		}
@@ -258,8 +467,8 @@ func TestExchangeAPI_TestModifyOrder(t *testing.T) {
 	modifyOrderRequest := ModifyOrderRequest{
 		OrderId:    res.Response.Data.Statuses[0].Resting.OrderId,
 		Coin:       coin,
-		Sz:         size,
-		LimitPx:    newPx,
+		Sz:         NewDecimalFromFloat(size),
+		LimitPx:    NewDecimalFromFloat(newPx),
 		OrderType:  orderType,
 		IsBuy:      true,
 		ReduceOnly: false,
@@ -355,7 +564,7 @@ func TestExchangeAPI_TestWithdraw(t *testing.T) {
 	}
 	t.Logf("GetAccountState() = %v", stateBefore)
 	balanceBefore := stateBefore.Withdrawable
-	if balanceBefore < withdrawAmount {
+	if balanceBefore.LessThan(NewDecimalFromFloat(withdrawAmount)) {
 		t.Errorf("Insufficient balance: %v", stateBefore)
 	}
 	accountAddress := exchangeAPI.AccountAddress() // withdraw to the same address
@@ -376,7 +585,7 @@ func TestExchageAPI_TestMarketOrderSpot(t *testing.T) {
 	}
 	t.Logf("MakeOpen() = %v", res)
 	avgPrice := res.Response.Data.Statuses[0].Filled.AvgPx
-	if avgPrice == 0 {
+	if avgPrice.IsZero() {
 		t.Errorf("res.Response.Data.Statuses[0].Filled.AvgPx = %v", avgPrice)
 	}
 }
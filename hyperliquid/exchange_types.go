@@ -3,6 +3,9 @@ package hyperliquid
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
 
 type RsvSignature struct {
@@ -19,6 +22,23 @@ type ExchangeRequest struct {
 	VaultAddress *string      `json:"vaultAddress,omitempty" msgpack:",omitempty"`
 }
 
+// UnmarshalJSON keeps the action as json.RawMessage, because the signature of L1 actions
+// depends on the order of their keys (see RecoverSigner).
+func (req *ExchangeRequest) UnmarshalJSON(data []byte) error {
+	type Alias ExchangeRequest
+	aux := &struct {
+		Action json.RawMessage `json:"action"`
+		*Alias
+	}{
+		Alias: (*Alias)(req),
+	}
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+	req.Action = aux.Action
+	return nil
+}
+
 type AssetInfo struct {
 	SzDecimals  int
 	WeiDecimals int
@@ -27,13 +47,62 @@ type AssetInfo struct {
 }
 
 type OrderRequest struct {
-	Coin       string    `json:"coin"`
-	IsBuy      bool      `json:"is_buy"`
-	Sz         float64   `json:"sz"`
-	LimitPx    float64   `json:"limit_px"`
-	OrderType  OrderType `json:"order_type"`
-	ReduceOnly bool      `json:"reduce_only"`
-	Cloid      string    `json:"cloid,omitempty"`
+	Coin       string       `json:"coin"`
+	IsBuy      bool         `json:"is_buy"`
+	Sz         Decimal      `json:"sz"`
+	LimitPx    Decimal      `json:"limit_px"`
+	OrderType  OrderType    `json:"order_type"`
+	ReduceOnly bool         `json:"reduce_only"`
+	Cloid      string       `json:"cloid,omitempty"`
+	PxRounding RoundingMode `json:"-"` // How LimitPx is rounded to the tick size, default RoundNearest
+	SzRounding RoundingMode `json:"-"` // How Sz is rounded to the lot size, default RoundNearest
+}
+
+// RoundingMode selects how prices and sizes are rounded to the allowed precision.
+type RoundingMode int
+
+const (
+	RoundNearest    RoundingMode = iota // Round half away from zero
+	RoundPassive                        // Away from the market: buy prices down, sell prices up. Sizes down
+	RoundAggressive                     // Into the market: buy prices up, sell prices down. Sizes up
+	RoundFloor                          // Toward negative infinity
+	RoundCeil                           // Toward positive infinity
+)
+
+// ForSide resolves RoundPassive and RoundAggressive to RoundFloor or RoundCeil for a price on the given side.
+// Other modes are returned as is.
+func (m RoundingMode) ForSide(isBuy bool) RoundingMode {
+	switch {
+	case m == RoundPassive && isBuy, m == RoundAggressive && !isBuy:
+		return RoundFloor
+	case m == RoundPassive, m == RoundAggressive:
+		return RoundCeil
+	}
+	return m
+}
+
+// NewOrderRequest builds an OrderRequest from float64 values.
+// Size determines the side: positive to buy, negative to sell.
+//
+//	NewOrderRequest("ETH", -0.1, 2500.5, OrderType{Limit: &LimitOrderType{Tif: TifGtc}}, false) // Sell 0.1 ETH
+func NewOrderRequest(coin string, size float64, px float64, orderType OrderType, reduceOnly bool) OrderRequest {
+	return OrderRequest{
+		Coin:       coin,
+		IsBuy:      IsBuy(size),
+		Sz:         NewDecimalFromFloat(math.Abs(size)),
+		LimitPx:    NewDecimalFromFloat(px),
+		OrderType:  orderType,
+		ReduceOnly: reduceOnly,
+	}
+}
+
+// WithRounding returns a copy of the order that rounds its price and size with the given modes.
+//
+//	NewOrderRequest("ETH", 0.12345, 2500.55, orderType, false).WithRounding(RoundPassive, RoundFloor) // Buy 0.1234 ETH at 2500.5
+func (req OrderRequest) WithRounding(px RoundingMode, sz RoundingMode) OrderRequest {
+	req.PxRounding = px
+	req.SzRounding = sz
+	return req
 }
 
 type OrderType struct {
@@ -94,15 +163,21 @@ type ModifyOrderAction struct {
 	Modifies []ModifyOrderWire `msgpack:"modifies" json:"modifies"`
 }
 
+func (ModifyOrderAction) ActionType() string { return "batchModify" }
+
+func (ModifyOrderAction) NewResponse() *OrderResponse { return &OrderResponse{} }
+
 type ModifyOrderRequest struct {
-	OrderId    int       `json:"oid"`
-	Coin       string    `json:"coin"`
-	IsBuy      bool      `json:"is_buy"`
-	Sz         float64   `json:"sz"`
-	LimitPx    float64   `json:"limit_px"`
-	OrderType  OrderType `json:"order_type"`
-	ReduceOnly bool      `json:"reduce_only"`
-	Cloid      string    `json:"cloid,omitempty"`
+	OrderId    int          `json:"oid"`
+	Coin       string       `json:"coin"`
+	IsBuy      bool         `json:"is_buy"`
+	Sz         Decimal      `json:"sz"`
+	LimitPx    Decimal      `json:"limit_px"`
+	OrderType  OrderType    `json:"order_type"`
+	ReduceOnly bool         `json:"reduce_only"`
+	Cloid      string       `json:"cloid,omitempty"`
+	PxRounding RoundingMode `json:"-"` // How LimitPx is rounded to the tick size, default RoundNearest
+	SzRounding RoundingMode `json:"-"` // How Sz is rounded to the lot size, default RoundNearest
 }
 
 type OrderTypeWire struct {
@@ -116,6 +191,10 @@ type PlaceOrderAction struct {
 	Grouping Grouping    `msgpack:"grouping" json:"grouping"`
 }
 
+func (PlaceOrderAction) ActionType() string { return "order" }
+
+func (PlaceOrderAction) NewResponse() *OrderResponse { return &OrderResponse{} }
+
 type OrderResponse struct {
 	Status   string             `json:"status"`
 	Response OrderInnerResponse `json:"response"`
@@ -169,6 +248,10 @@ type CancelOidOrderAction struct {
 	Cancels []CancelOidWire `msgpack:"cancels" json:"cancels"`
 }
 
+func (CancelOidOrderAction) ActionType() string { return "cancel" }
+
+func (CancelOidOrderAction) NewResponse() *OrderResponse { return &OrderResponse{} }
+
 type CancelOidWire struct {
 	Asset int `msgpack:"a" json:"a"`
 	Oid   int `msgpack:"o" json:"o"`
@@ -184,6 +267,10 @@ type CancelCloidOrderAction struct {
 	Cancels []CancelCloidWire `msgpack:"cancels" json:"cancels"`
 }
 
+func (CancelCloidOrderAction) ActionType() string { return "cancelByCloid" }
+
+func (CancelCloidOrderAction) NewResponse() *OrderResponse { return &OrderResponse{} }
+
 type RestingStatus struct {
 	OrderId int    `json:"oid"`
 	Cloid   string `json:"cloid,omitempty"`
@@ -199,8 +286,8 @@ type CloseRequest struct {
 
 type FilledStatus struct {
 	OrderId int     `json:"oid"`
-	AvgPx   float64 `json:"avgPx,string"`
-	TotalSz float64 `json:"totalSz,string"`
+	AvgPx   Decimal `json:"avgPx"`
+	TotalSz Decimal `json:"totalSz"`
 	Cloid   string  `json:"cloid,omitempty"`
 }
 
@@ -217,6 +304,10 @@ type UpdateLeverageAction struct {
 	Leverage int    `msgpack:"leverage" json:"leverage"`
 }
 
+func (UpdateLeverageAction) ActionType() string { return "updateLeverage" }
+
+func (UpdateLeverageAction) NewResponse() *DefaultExchangeResponse { return &DefaultExchangeResponse{} }
+
 type DefaultExchangeResponse struct {
 	Status   string `json:"status"`
 	Response struct {
@@ -224,17 +315,6 @@ type DefaultExchangeResponse struct {
 	} `json:"response"`
 }
 
-// Depending on Type this struct can has different non-nil fields
-type NonFundingDelta struct {
-	Type   string  `json:"type"`
-	Usdc   float64 `json:"usdc,string,omitempty"`
-	Amount float64 `json:"amount,string,omitempty"`
-	ToPerp bool    `json:"toPerp,omitempty"`
-	Token  string  `json:"token,omitempty"`
-	Fee    float64 `json:"fee,string,omitempty"`
-	Nonce  int64   `json:"nonce"`
-}
-
 type FundingDelta struct {
 	Asset       string `json:"coin"`
 	FundingRate string `json:"fundingRate"`
@@ -245,15 +325,15 @@ type FundingDelta struct {
 type Withdrawal struct {
 	Time   int64   `json:"time"`
 	Hash   string  `json:"hash"`
-	Amount float64 `json:"usdc"`
-	Fee    float64 `json:"fee"`
+	Amount Decimal `json:"usdc"`
+	Fee    Decimal `json:"fee"`
 	Nonce  int64   `json:"nonce"`
 }
 
 type Deposit struct {
 	Hash   string  `json:"hash,omitempty"`
 	Time   int64   `json:"time,omitempty"`
-	Amount float64 `json:"usdc,omitempty"`
+	Amount Decimal `json:"usdc,omitempty"`
 }
 
 type WithdrawAction struct {
@@ -265,7 +345,153 @@ type WithdrawAction struct {
 	SignatureChainID string `msgpack:"signatureChainId" json:"signatureChainId"`
 }
 
+func (WithdrawAction) ActionType() string { return "withdraw3" }
+
+func (WithdrawAction) NewResponse() *WithdrawResponse { return &WithdrawResponse{} }
+
+func (WithdrawAction) EIP712Types() (string, []apitypes.Type) {
+	userSigned := userSignedActionTypes["withdraw3"]
+	return userSigned.PrimaryType, userSigned.Types
+}
+
+func (action WithdrawAction) WithSignatureParams(nonce uint64, signatureChainId string, hyperliquidChain string) UserSignedAction {
+	action.Time = nonce
+	action.SignatureChainID = signatureChainId
+	action.HyperliquidChain = hyperliquidChain
+	return action
+}
+
 type WithdrawResponse struct {
 	Status string `json:"status"`
 	Nonce  int64
 }
+
+// UsdSendAction transfers USDC between perp accounts.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#core-usdc-transfer
+type UsdSendAction struct {
+	Type             string `msgpack:"type" json:"type"`
+	Destination      string `msgpack:"destination" json:"destination"`
+	Amount           string `msgpack:"amount" json:"amount"`
+	Time             uint64 `msgpack:"time" json:"time"`
+	HyperliquidChain string `msgpack:"hyperliquidChain" json:"hyperliquidChain"`
+	SignatureChainID string `msgpack:"signatureChainId" json:"signatureChainId"`
+}
+
+func (UsdSendAction) ActionType() string { return "usdSend" }
+
+func (UsdSendAction) NewResponse() *DefaultExchangeResponse { return &DefaultExchangeResponse{} }
+
+func (UsdSendAction) EIP712Types() (string, []apitypes.Type) {
+	userSigned := userSignedActionTypes["usdSend"]
+	return userSigned.PrimaryType, userSigned.Types
+}
+
+func (action UsdSendAction) WithSignatureParams(nonce uint64, signatureChainId string, hyperliquidChain string) UserSignedAction {
+	action.Time = nonce
+	action.SignatureChainID = signatureChainId
+	action.HyperliquidChain = hyperliquidChain
+	return action
+}
+
+// SpotSendAction transfers a spot token between spot accounts.
+// Token is "name:tokenId", e.g. "PURR:0xc4bf3f870c0e9465323c0b6ed28096c2".
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#core-spot-transfer
+type SpotSendAction struct {
+	Type             string `msgpack:"type" json:"type"`
+	Destination      string `msgpack:"destination" json:"destination"`
+	Token            string `msgpack:"token" json:"token"`
+	Amount           string `msgpack:"amount" json:"amount"`
+	Time             uint64 `msgpack:"time" json:"time"`
+	HyperliquidChain string `msgpack:"hyperliquidChain" json:"hyperliquidChain"`
+	SignatureChainID string `msgpack:"signatureChainId" json:"signatureChainId"`
+}
+
+func (SpotSendAction) ActionType() string { return "spotSend" }
+
+func (SpotSendAction) NewResponse() *DefaultExchangeResponse { return &DefaultExchangeResponse{} }
+
+func (SpotSendAction) EIP712Types() (string, []apitypes.Type) {
+	userSigned := userSignedActionTypes["spotSend"]
+	return userSigned.PrimaryType, userSigned.Types
+}
+
+func (action SpotSendAction) WithSignatureParams(nonce uint64, signatureChainId string, hyperliquidChain string) UserSignedAction {
+	action.Time = nonce
+	action.SignatureChainID = signatureChainId
+	action.HyperliquidChain = hyperliquidChain
+	return action
+}
+
+// ApproveAgentAction authorizes an agent (API wallet) to sign L1 actions for the user.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/nonces-and-api-wallets
+type ApproveAgentAction struct {
+	Type             string `msgpack:"type" json:"type"`
+	AgentAddress     string `msgpack:"agentAddress" json:"agentAddress"`
+	AgentName        string `msgpack:"agentName" json:"agentName"`
+	Nonce            uint64 `msgpack:"nonce" json:"nonce"`
+	HyperliquidChain string `msgpack:"hyperliquidChain" json:"hyperliquidChain"`
+	SignatureChainID string `msgpack:"signatureChainId" json:"signatureChainId"`
+}
+
+func (ApproveAgentAction) ActionType() string { return "approveAgent" }
+
+func (ApproveAgentAction) NewResponse() *DefaultExchangeResponse { return &DefaultExchangeResponse{} }
+
+func (ApproveAgentAction) EIP712Types() (string, []apitypes.Type) {
+	userSigned := userSignedActionTypes["approveAgent"]
+	return userSigned.PrimaryType, userSigned.Types
+}
+
+func (action ApproveAgentAction) WithSignatureParams(nonce uint64, signatureChainId string, hyperliquidChain string) UserSignedAction {
+	action.Nonce = nonce
+	action.SignatureChainID = signatureChainId
+	action.HyperliquidChain = hyperliquidChain
+	return action
+}
+
+// ConvertToMultiSigUserAction converts the user to a multi-sig account.
+// Signers is the JSON of MultiSigSigners.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/hypercore/multi-sig
+type ConvertToMultiSigUserAction struct {
+	Type             string `msgpack:"type" json:"type"`
+	Signers          string `msgpack:"signers" json:"signers"`
+	Nonce            uint64 `msgpack:"nonce" json:"nonce"`
+	HyperliquidChain string `msgpack:"hyperliquidChain" json:"hyperliquidChain"`
+	SignatureChainID string `msgpack:"signatureChainId" json:"signatureChainId"`
+}
+
+func (ConvertToMultiSigUserAction) ActionType() string { return "convertToMultiSigUser" }
+
+func (ConvertToMultiSigUserAction) NewResponse() *DefaultExchangeResponse {
+	return &DefaultExchangeResponse{}
+}
+
+func (ConvertToMultiSigUserAction) EIP712Types() (string, []apitypes.Type) {
+	userSigned := userSignedActionTypes["convertToMultiSigUser"]
+	return userSigned.PrimaryType, userSigned.Types
+}
+
+func (action ConvertToMultiSigUserAction) WithSignatureParams(nonce uint64, signatureChainId string, hyperliquidChain string) UserSignedAction {
+	action.Nonce = nonce
+	action.SignatureChainID = signatureChainId
+	action.HyperliquidChain = hyperliquidChain
+	return action
+}
+
+// MultiSigAction sends an action for a multi-sig account with the signatures of its authorized users.
+// It is signed by the outer signer, one of the authorized users (see MultiSigCollector).
+type MultiSigAction struct {
+	Type             string          `msgpack:"type" json:"type"`
+	SignatureChainID string          `msgpack:"signatureChainId" json:"signatureChainId"`
+	Signatures       []RsvSignature  `msgpack:"signatures" json:"signatures"`
+	Payload          MultiSigPayload `msgpack:"payload" json:"payload"`
+}
+
+// MultiSigPayload is the inner action of a multi-sig account. Addresses are lowercase.
+type MultiSigPayload struct {
+	MultiSigUser string `msgpack:"multiSigUser" json:"multiSigUser"`
+	OuterSigner  string `msgpack:"outerSigner" json:"outerSigner"`
+	Action       any    `msgpack:"action" json:"action"`
+}
+
+func (MultiSigAction) ActionType() string { return "multiSig" }
@@ -14,10 +14,12 @@ type Hyperliquid struct {
 // PrivateKey can be empty if you only need to use the public endpoints.
 // AccountAddress is the default account address for the API that can be changed with SetAccountAddress().
 // AccountAddress may be different from the address build from the private key due to Hyperliquid's account system.
+// Signer can be set instead of PrivateKey to keep the key outside of the process (see KeystoreSigner and RemoteSigner).
 type HyperliquidClientConfig struct {
 	IsMainnet      bool
 	PrivateKey     string
 	AccountAddress string
+	Signer         Signer
 }
 
 func NewHyperliquid(config *HyperliquidClientConfig) *Hyperliquid {
@@ -32,7 +34,11 @@ func NewHyperliquid(config *HyperliquidClientConfig) *Hyperliquid {
 		defaultConfig = config
 	}
 	exchangeAPI := NewExchangeAPI(defaultConfig.IsMainnet)
-	exchangeAPI.SetPrivateKey(defaultConfig.PrivateKey)
+	if defaultConfig.Signer != nil {
+		exchangeAPI.SetSigner(defaultConfig.Signer)
+	} else {
+		exchangeAPI.SetPrivateKey(defaultConfig.PrivateKey)
+	}
 	exchangeAPI.SetAccountAddress(defaultConfig.AccountAddress)
 	infoAPI := NewInfoAPI(defaultConfig.IsMainnet)
 	infoAPI.SetAccountAddress(defaultConfig.AccountAddress)
@@ -55,6 +61,23 @@ func (h *Hyperliquid) SetPrivateKey(privateKey string) error {
 	return nil
 }
 
+func (h *Hyperliquid) SetSigner(signer Signer) {
+	h.ExchangeAPI.SetSigner(signer)
+}
+
+func (h *Hyperliquid) Signer() Signer {
+	return h.ExchangeAPI.Signer()
+}
+
+func (h *Hyperliquid) SetKeyManager(keyManager *PKeyManager) {
+	h.ExchangeAPI.SetKeyManager(keyManager)
+}
+
+// Close wipes the private key of the exchange API.
+func (h *Hyperliquid) Close() error {
+	return h.ExchangeAPI.Close()
+}
+
 func (h *Hyperliquid) SetAccountAddress(accountAddress string) {
 	h.ExchangeAPI.SetAccountAddress(accountAddress)
 	h.InfoAPI.SetAccountAddress(accountAddress)
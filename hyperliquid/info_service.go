@@ -2,7 +2,10 @@ package hyperliquid
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
+	"sort"
 	"strconv"
 )
 
@@ -14,21 +17,48 @@ type IInfoAPI interface {
 	GetAllMids() (*map[string]string, error)
 	GetOpenOrders(address string) (*[]Order, error)
 	GetAccountOpenOrders() (*[]Order, error)
+	GetFrontendOpenOrders(address string) (*[]Order, error)
+	GetAccountFrontendOpenOrders() (*[]Order, error)
+	GetHistoricalOrders(address string) (*[]HistoricalOrder, error)
+	GetAccountHistoricalOrders() (*[]HistoricalOrder, error)
+	GetOrderStatus(address string, oid int64) (*HistoricalOrder, error)
+	GetOrderStatusByCloid(address string, cloid string) (*HistoricalOrder, error)
 	GetUserFills(address string) (*[]OrderFill, error)
 	GetAccountFills() (*[]OrderFill, error)
+	GetUserFillsByTime(address string, startTime int64, endTime int64, aggregateByTime bool) iter.Seq2[OrderFill, error]
+	GetAccountFillsByTime(startTime int64, endTime int64, aggregateByTime bool) iter.Seq2[OrderFill, error]
 	GetUserRateLimits(address string) (*float64, error)
+	GetUserFees(address string) (*UserFees, error)
+	GetAccountFees() (*UserFees, error)
+	GetMultiSigSigners(address string) (*MultiSigSigners, error)
 	GetL2BookSnapshot(coin string) (*L2BookSnapshot, error)
-	GetCandleSnapshot(coin string, interval string, startTime int64, endTime int64) (*CandleSnapshot, error)
+	GetAggregatedL2BookSnapshot(coin string, nSigFigs int, mantissa int) (*L2BookSnapshot, error)
+	GetCandleSnapshot(coin string, interval CandleInterval, startTime int64, endTime int64) (*[]CandleSnapshot, error)
+	GetCandles(coin string, interval CandleInterval, startTime int64, endTime int64) (*[]CandleSnapshot, error)
 
 	// PERPETUALS INFO API ENDPOINTS
 	GetMeta() (*Meta, error)
+	GetMetaAndAssetCtxs() (*[]AssetContext, error)
 	GetUserState(address string) (*UserState, error)
 	GetAccountState() (*UserState, error)
+	GetActiveAssetData(address string, coin string) (*ActiveAssetData, error)
+	GetAccountActiveAssetData(coin string) (*ActiveAssetData, error)
+	GetPortfolio(address string) (*Portfolio, error)
+	GetAccountPortfolio() (*Portfolio, error)
 	GetFundingUpdates(address string, startTime int64, endTime int64) (*[]FundingUpdate, error)
 	GetAccountFundingUpdates(startTime int64, endTime int64) (*[]FundingUpdate, error)
 	GetNonFundingUpdates(address string, startTime int64, endTime int64) (*[]NonFundingUpdate, error)
 	GetAccountNonFundingUpdates(startTime int64, endTime int64) (*[]NonFundingUpdate, error)
 	GetHistoricalFundingRates() (*[]HistoricalFundingRate, error)
+	GetPredictedFundings() (*PredictedFundings, error)
+	GetPerpsAtOpenInterestCap() (*[]string, error)
+
+	// SPOT INFO API ENDPOINTS
+	GetSpotMeta() (*SpotMeta, error)
+	GetSpotMetaAndAssetCtxs() (*[]SpotAssetContext, error)
+	GetAllSpotPrices() (*map[string]string, error)
+	GetUserStateSpot(address string) (*UserStateSpot, error)
+	GetAccountStateSpot() (*UserStateSpot, error)
 
 	// Additional helper functions
 	GetMartketPx(coin string) (float64, error)
@@ -39,8 +69,9 @@ type IInfoAPI interface {
 
 type InfoAPI struct {
 	Client
-	baseEndpoint string
-	spotMeta     map[string]AssetInfo
+	baseEndpoint   string
+	spotMeta       map[string]AssetInfo
+	candleCacheDir string
 }
 
 // NewInfoAPI returns a new instance of the InfoAPI struct.
@@ -64,6 +95,13 @@ func (api *InfoAPI) Endpoint() string {
 	return api.baseEndpoint
 }
 
+// SetCandleCacheDir enables caching of GetCandles results in the given directory.
+// Closed candles are stored in one JSON file per network, coin and interval and are not fetched again.
+// Pass an empty string to disable the cache.
+func (api *InfoAPI) SetCandleCacheDir(dir string) {
+	api.candleCacheDir = dir
+}
+
 // Retrieve mids for all actively traded coins
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#retrieve-mids-for-all-actively-traded-coins
 func (api *InfoAPI) GetAllMids() (*map[string]string, error) {
@@ -73,38 +111,26 @@ func (api *InfoAPI) GetAllMids() (*map[string]string, error) {
 	return MakeUniversalRequest[map[string]string](api, request)
 }
 
-// Retrieve spot meta and asset contexts
+// Retrieve mid prices of all spot pairs keyed by pair name (e.g. "@107")
+// The prices are the strings returned by the exchange.
+// Pairs without a mid price (e.g. with an empty book) are included with an empty string.
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint/spot#retrieve-spot-asset-contexts
 func (api *InfoAPI) GetAllSpotPrices() (*map[string]string, error) {
 	request := InfoRequest{
 		Typez: "spotMetaAndAssetCtxs",
 	}
-	response, err := MakeUniversalRequest[SpotMetaAndAssetCtxsResponse](api, request)
-	if err != nil {
-		return nil, err
-	}
-
-	marketsData, ok := response[1].([]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid markets data format")
-	}
-
-	result := make(map[string]string)
-
-	marketBytes, err := json.Marshal(marketsData)
+	response, err := MakeUniversalRequest[[2]json.RawMessage](api, request)
 	if err != nil {
 		return nil, err
 	}
-
 	var markets []Market
-	if err := json.Unmarshal(marketBytes, &markets); err != nil {
-		return nil, err
+	if err := json.Unmarshal(response[1], &markets); err != nil {
+		return nil, fmt.Errorf("invalid markets data format: %w", err)
 	}
-
+	result := make(map[string]string, len(markets))
 	for _, market := range markets {
 		result[market.Coin] = market.MidPx
 	}
-
 	return &result, nil
 }
 
@@ -125,6 +151,72 @@ func (api *InfoAPI) GetAccountOpenOrders() (*[]Order, error) {
 	return api.GetOpenOrders(api.AccountAddress())
 }
 
+// Retrieve a user's open orders with additional frontend info
+// Unlike GetOpenOrders it includes trigger conditions, order type and TP/SL children
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#retrieve-a-users-open-orders-with-additional-frontend-info
+func (api *InfoAPI) GetFrontendOpenOrders(address string) (*[]Order, error) {
+	request := InfoRequest{
+		User:  address,
+		Typez: "frontendOpenOrders",
+	}
+	return MakeUniversalRequest[[]Order](api, request)
+}
+
+// Retrieve account's open orders with additional frontend info
+// The same as GetFrontendOpenOrders but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountFrontendOpenOrders() (*[]Order, error) {
+	return api.GetFrontendOpenOrders(api.AccountAddress())
+}
+
+// Retrieve a user's historical orders (including filled, canceled and rejected ones)
+// Returns at most 2000 most recent historical orders
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#retrieve-a-users-historical-orders
+func (api *InfoAPI) GetHistoricalOrders(address string) (*[]HistoricalOrder, error) {
+	request := InfoRequest{
+		User:  address,
+		Typez: "historicalOrders",
+	}
+	return MakeUniversalRequest[[]HistoricalOrder](api, request)
+}
+
+// Retrieve account's historical orders
+// The same as GetHistoricalOrders but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountHistoricalOrders() (*[]HistoricalOrder, error) {
+	return api.GetHistoricalOrders(api.AccountAddress())
+}
+
+// Query the status of an order by its order id.
+// It returns nil if the order is unknown.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#query-order-status-by-oid-or-cloid
+func (api *InfoAPI) GetOrderStatus(address string, oid int64) (*HistoricalOrder, error) {
+	return api.getOrderStatus(address, oid)
+}
+
+// Query the status of an order by its client order id.
+// It returns nil if the order is unknown.
+func (api *InfoAPI) GetOrderStatusByCloid(address string, cloid string) (*HistoricalOrder, error) {
+	return api.getOrderStatus(address, cloid)
+}
+
+// Helper function to query the status of an order by oid or cloid
+func (api *InfoAPI) getOrderStatus(address string, oid any) (*HistoricalOrder, error) {
+	request := OrderStatusRequest{
+		User:  address,
+		Typez: "orderStatus",
+		Oid:   oid,
+	}
+	response, err := MakeUniversalRequest[OrderStatusResponse](api, request)
+	if err != nil {
+		return nil, err
+	}
+	if response.Status != "order" {
+		return nil, nil
+	}
+	return response.Order, nil
+}
+
 // Retrieve a user's fills
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#retrieve-a-users-fills
 func (api *InfoAPI) GetUserFills(address string) (*[]OrderFill, error) {
@@ -142,6 +234,84 @@ func (api *InfoAPI) GetAccountFills() (*[]OrderFill, error) {
 	return api.GetUserFills(api.AccountAddress())
 }
 
+// ErrFillsTruncated is returned by GetUserFillsByTime when a full page of fills has the same time.
+// The fills after the page can't be requested, so the history is incomplete.
+var ErrFillsTruncated = errors.New("more fills in one millisecond than the API returns per request")
+
+// Retrieve a user's fills by time
+// The API returns at most 2000 fills per request, so the range is paged automatically:
+// the next request starts at the time of the latest fill received and fills already
+// yielded are skipped by Tid. Fills are yielded in ascending time order.
+// If more than 2000 fills have the same time, ErrFillsTruncated is yielded and the iteration stops.
+// An endTime of 0 means up to now.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#retrieve-a-users-fills-by-time
+//
+// Example:
+//
+//	for fill, err := range api.GetUserFillsByTime(address, startTime, endTime, false) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (api *InfoAPI) GetUserFillsByTime(address string, startTime int64, endTime int64, aggregateByTime bool) iter.Seq2[OrderFill, error] {
+	return func(yield func(OrderFill, error) bool) {
+		// Tids of the fills at the page boundary, the only ones that can be returned twice
+		seen := make(map[int64]struct{})
+		for {
+			request := InfoRequest{
+				User:            address,
+				Typez:           "userFillsByTime",
+				StartTime:       startTime,
+				EndTime:         endTime,
+				AggregateByTime: aggregateByTime,
+			}
+			fills, err := MakeUniversalRequest[[]OrderFill](api, request)
+			if err != nil {
+				yield(OrderFill{}, err)
+				return
+			}
+			page := *fills
+			sort.SliceStable(page, func(i, j int) bool {
+				return page[i].Time < page[j].Time
+			})
+			lastTime := startTime
+			for _, fill := range page {
+				if _, ok := seen[fill.Tid]; ok {
+					continue
+				}
+				if !yield(fill, nil) {
+					return
+				}
+				if fill.Time > lastTime {
+					lastTime = fill.Time
+					clear(seen)
+				}
+				seen[fill.Tid] = struct{}{}
+			}
+			if len(page) < USER_FILLS_BY_TIME_LIMIT {
+				return
+			}
+			if lastTime == startTime {
+				// A full page within a single millisecond, the next request would return the same page
+				yield(OrderFill{}, fmt.Errorf("%w: %d fills at %d", ErrFillsTruncated, len(page), lastTime))
+				return
+			}
+			if endTime != 0 && lastTime > endTime {
+				return
+			}
+			startTime = lastTime
+		}
+	}
+}
+
+// Retrieve account's fills by time
+// The same as GetUserFillsByTime but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountFillsByTime(startTime int64, endTime int64, aggregateByTime bool) iter.Seq2[OrderFill, error] {
+	return api.GetUserFillsByTime(api.AccountAddress(), startTime, endTime, aggregateByTime)
+}
+
 // Query user rate limits
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#query-user-rate-limits
 func (api *InfoAPI) GetUserRateLimits(address string) (*RatesLimits, error) {
@@ -159,19 +329,64 @@ func (api *InfoAPI) GetAccountRateLimits() (*RatesLimits, error) {
 	return api.GetUserRateLimits(api.AccountAddress())
 }
 
+// Query user fees: daily volume, current maker/taker rates, discounts and the fee schedule
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#query-a-users-fees
+func (api *InfoAPI) GetUserFees(address string) (*UserFees, error) {
+	request := InfoRequest{
+		User:  address,
+		Typez: "userFees",
+	}
+	return MakeUniversalRequest[UserFees](api, request)
+}
+
+// Query account fees
+// The same as GetUserFees but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountFees() (*UserFees, error) {
+	return api.GetUserFees(api.AccountAddress())
+}
+
 // L2 Book snapshot
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#l2-book-snapshot
 func (api *InfoAPI) GetL2BookSnapshot(coin string) (*L2BookSnapshot, error) {
-	request := InfoRequest{
+	request := L2BookRequest{
 		Typez: "l2Book",
 		Coin:  coin,
 	}
 	return MakeUniversalRequest[L2BookSnapshot](api, request)
 }
 
+// L2 Book snapshot aggregated to nSigFigs significant figures (2-5)
+// Mantissa (1, 2 or 5) is only allowed with nSigFigs=5, pass 0 to leave it unset.
+//
+// Example:
+//
+//	api.GetAggregatedL2BookSnapshot("BTC", 2, 0) // levels rounded to 2 significant figures
+//	api.GetAggregatedL2BookSnapshot("BTC", 5, 5) // levels grouped by 5 units of the 5th figure
+func (api *InfoAPI) GetAggregatedL2BookSnapshot(coin string, nSigFigs int, mantissa int) (*L2BookSnapshot, error) {
+	if nSigFigs < 2 || nSigFigs > 5 {
+		return nil, APIError{Message: fmt.Sprintf("Invalid nSigFigs: %d. Allowed values: 2, 3, 4, 5", nSigFigs)}
+	}
+	request := L2BookRequest{
+		Typez:    "l2Book",
+		Coin:     coin,
+		NSigFigs: &nSigFigs,
+	}
+	if mantissa != 0 {
+		if nSigFigs != 5 || (mantissa != 1 && mantissa != 2 && mantissa != 5) {
+			return nil, APIError{Message: fmt.Sprintf("Invalid mantissa: %d. Allowed values: 1, 2, 5 with nSigFigs=5", mantissa)}
+		}
+		request.Mantissa = &mantissa
+	}
+	return MakeUniversalRequest[L2BookSnapshot](api, request)
+}
+
 // Candle snapshot (Only the most recent 5000 candles are available)
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#candle-snapshot
-func (api *InfoAPI) GetCandleSnapshot(coin string, interval string, startTime int64, endTime int64) (*[]CandleSnapshot, error) {
+func (api *InfoAPI) GetCandleSnapshot(coin string, interval CandleInterval, startTime int64, endTime int64) (*[]CandleSnapshot, error) {
+	if !interval.IsValid() {
+		return nil, APIError{Message: fmt.Sprintf("Invalid candle interval: %s", interval)}
+	}
 	request := CandleSnapshotRequest{
 		Typez: "candleSnapshot",
 		Req: CandleSnapshotSubRequest{
@@ -184,6 +399,73 @@ func (api *InfoAPI) GetCandleSnapshot(coin string, interval string, startTime in
 	return MakeUniversalRequest[[]CandleSnapshot](api, request)
 }
 
+// Candles overlapping the time range, sorted by open time
+// Unlike GetCandleSnapshot the range is split into windows of at most 5000 candles,
+// the results are merged and de-duplicated by open time.
+// If SetCandleCacheDir() was called, closed candles are read from and written to the cache.
+// Use CandleGaps() to check the result for missing candles.
+func (api *InfoAPI) GetCandles(coin string, interval CandleInterval, startTime int64, endTime int64) (*[]CandleSnapshot, error) {
+	if !interval.IsValid() {
+		return nil, APIError{Message: fmt.Sprintf("Invalid candle interval: %s", interval)}
+	}
+	if startTime > endTime {
+		return nil, APIError{Message: fmt.Sprintf("Invalid time range: %d > %d", startTime, endTime)}
+	}
+	var cache *candleCache
+	if api.candleCacheDir != "" {
+		var err error
+		cache, err = loadCandleCache(api.candleCacheDir, api.IsMainnet(), coin, interval)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	candles := make(map[int64]CandleSnapshot)
+	if cache != nil {
+		for _, candle := range cache.Candles {
+			candles[candle.OpenTime] = candle
+		}
+	}
+	// Only candles closed before now are final and can be cached
+	closedBefore := api.clock().UnixMilli()
+	window := interval.Duration().Milliseconds() * CANDLE_SNAPSHOT_LIMIT
+	for from := startTime; from <= endTime; from += window {
+		to := min(from+window-1, endTime)
+		if cache != nil && cache.covers(from, to) {
+			continue
+		}
+		snapshot, err := api.GetCandleSnapshot(coin, interval, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, candle := range *snapshot {
+			candles[candle.OpenTime] = candle
+		}
+		if cache != nil {
+			cache.add(*snapshot, from, to, interval, closedBefore)
+		}
+	}
+	if cache != nil {
+		if err := cache.save(); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]CandleSnapshot, 0, len(candles))
+	for _, candle := range candles {
+		if candle.OpenTime <= endTime && candle.CloseTime >= startTime {
+			result = append(result, candle)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].OpenTime < result[j].OpenTime
+	})
+	if gaps := CandleGaps(result, interval); len(gaps) > 0 {
+		api.debug("GetCandles %s %s: %d gaps in the result", coin, interval, len(gaps))
+	}
+	return &result, nil
+}
+
 // Retrieve perpetuals metadata
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint/perpetuals#retrieve-perpetuals-metadata
 func (api *InfoAPI) GetMeta() (*Meta, error) {
@@ -193,6 +475,31 @@ func (api *InfoAPI) GetMeta() (*Meta, error) {
 	return MakeUniversalRequest[Meta](api, request)
 }
 
+// Retrieve perpetuals asset contexts (funding, open interest, mark/oracle/impact prices)
+// Each asset of the universe is returned together with its context and asset id
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint/perpetuals#retrieve-perpetuals-asset-contexts-includes-mark-price-current-funding-open-interest-etc
+func (api *InfoAPI) GetMetaAndAssetCtxs() (*[]AssetContext, error) {
+	request := InfoRequest{
+		Typez: "metaAndAssetCtxs",
+	}
+	response, err := MakeUniversalRequest[MetaAndAssetCtxs](api, request)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Meta.Universe) != len(response.Contexts) {
+		return nil, APIError{Message: fmt.Sprintf("metaAndAssetCtxs: %d assets but %d contexts", len(response.Meta.Universe), len(response.Contexts))}
+	}
+	result := make([]AssetContext, len(response.Meta.Universe))
+	for index, asset := range response.Meta.Universe {
+		result[index] = AssetContext{
+			AssetId: index,
+			Asset:   asset,
+			Context: response.Contexts[index],
+		}
+	}
+	return &result, nil
+}
+
 // Retrieve spot metadata
 func (api *InfoAPI) GetSpotMeta() (*SpotMeta, error) {
 	request := InfoRequest{
@@ -201,6 +508,40 @@ func (api *InfoAPI) GetSpotMeta() (*SpotMeta, error) {
 	return MakeUniversalRequest[SpotMeta](api, request)
 }
 
+// Retrieve spot asset contexts
+// Each pair of the spot universe is returned together with its base/quote tokens and context
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint/spot#retrieve-spot-asset-contexts
+func (api *InfoAPI) GetSpotMetaAndAssetCtxs() (*[]SpotAssetContext, error) {
+	request := InfoRequest{
+		Typez: "spotMetaAndAssetCtxs",
+	}
+	response, err := MakeUniversalRequest[SpotMetaAndAssetCtxs](api, request)
+	if err != nil {
+		return nil, err
+	}
+	contexts := make(map[string]SpotContext, len(response.Contexts))
+	for _, spotCtx := range response.Contexts {
+		contexts[spotCtx.Coin] = spotCtx
+	}
+	result := make([]SpotAssetContext, 0, len(response.Meta.Universe))
+	for _, pair := range response.Meta.Universe {
+		base, quote, ok := response.Meta.pairTokens(pair)
+		if !ok {
+			api.debug("spotMetaAndAssetCtxs: unknown tokens %v for pair %s", pair.Tokens, pair.Name)
+			continue
+		}
+		result = append(result, SpotAssetContext{
+			Coin:    pair.Name,
+			Name:    base.Name + "/" + quote.Name,
+			Pair:    pair,
+			Base:    base,
+			Quote:   quote,
+			Context: contexts[pair.Name],
+		})
+	}
+	return &result, nil
+}
+
 // Retrieve user's perpetuals account summary
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint/perpetuals#retrieve-users-perpetuals-account-summary
 func (api *InfoAPI) GetUserState(address string) (*UserState, error) {
@@ -218,6 +559,59 @@ func (api *InfoAPI) GetAccountState() (*UserState, error) {
 	return api.GetUserState(api.AccountAddress())
 }
 
+// Retrieve user's active asset data: leverage, max trade sizes and available to trade per side
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint/perpetuals#retrieve-users-active-asset-data
+func (api *InfoAPI) GetActiveAssetData(address string, coin string) (*ActiveAssetData, error) {
+	request := InfoRequest{
+		User:  address,
+		Typez: "activeAssetData",
+		Coin:  coin,
+	}
+	return MakeUniversalRequest[ActiveAssetData](api, request)
+}
+
+// Retrieve account's active asset data
+// The same as GetActiveAssetData but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountActiveAssetData(coin string) (*ActiveAssetData, error) {
+	return api.GetActiveAssetData(api.AccountAddress(), coin)
+}
+
+// Retrieve user's portfolio: account value and PnL history for day/week/month/allTime windows,
+// both for the total account and for perps only
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#query-a-users-portfolio
+func (api *InfoAPI) GetPortfolio(address string) (*Portfolio, error) {
+	request := InfoRequest{
+		User:  address,
+		Typez: "portfolio",
+	}
+	return MakeUniversalRequest[Portfolio](api, request)
+}
+
+// Retrieve account's portfolio
+// The same as GetPortfolio but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountPortfolio() (*Portfolio, error) {
+	return api.GetPortfolio(api.AccountAddress())
+}
+
+// Retrieve the authorized users and the signature threshold of a multi-sig account.
+// It returns nil if the user is not a multi-sig account.
+func (api *InfoAPI) GetMultiSigSigners(address string) (*MultiSigSigners, error) {
+	request := InfoRequest{
+		User:  address,
+		Typez: "userToMultiSigSigners",
+	}
+	signers, err := MakeUniversalRequest[MultiSigSigners](api, request)
+	if err != nil {
+		return nil, err
+	}
+	if len(signers.AuthorizedUsers) == 0 {
+		return nil, nil
+	}
+	return signers, nil
+}
+
 // Retrieve user's spot account summary
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint/spot#retrieve-a-users-token-balances
 func (api *InfoAPI) GetUserStateSpot(address string) (*UserStateSpot, error) {
@@ -285,6 +679,26 @@ func (api *InfoAPI) GetHistoricalFundingRates(coin string, startTime int64, endT
 	return MakeUniversalRequest[[]HistoricalFundingRate](api, request)
 }
 
+// Retrieve predicted funding rates for different venues (Hyperliquid, Binance, Bybit)
+// Use AnnualizedRate() to compare venues with different funding intervals
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint/perpetuals#retrieve-predicted-funding-rates-for-different-venues
+func (api *InfoAPI) GetPredictedFundings() (*PredictedFundings, error) {
+	request := InfoRequest{
+		Typez: "predictedFundings",
+	}
+	return MakeUniversalRequest[PredictedFundings](api, request)
+}
+
+// Query perps at open interest caps
+// New positions can't be opened on these coins until open interest goes down
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint/perpetuals#query-perps-at-open-interest-caps
+func (api *InfoAPI) GetPerpsAtOpenInterestCap() (*[]string, error) {
+	request := InfoRequest{
+		Typez: "perpsAtOpenInterestCap",
+	}
+	return MakeUniversalRequest[[]string](api, request)
+}
+
 // Helper function to get the market price of a given coin
 // The coin parameter is the name of the coin
 //
@@ -315,6 +729,9 @@ func (api *InfoAPI) GetSpotMarketPx(coin string) (float64, error) {
 		return 0, err
 	}
 	spotName := api.spotMeta[coin].SpotName
+	if (*spotPrices)[spotName] == "" {
+		return 0, APIError{Message: fmt.Sprintf("No mid price for spot coin %s", coin)}
+	}
 	parsed, err := strconv.ParseFloat((*spotPrices)[spotName], 32)
 	if err != nil {
 		return 0, err
@@ -332,13 +749,13 @@ func (api *InfoAPI) GetWithdrawals(address string) (*[]Withdrawal, error) {
 	}
 	var withdrawals []Withdrawal
 	for _, update := range *updates {
-		if update.Delta.Type == "withdraw" {
+		if delta, ok := update.Delta.(*WithdrawDelta); ok {
 			withrawal := Withdrawal{
 				Time:   update.Time,
 				Hash:   update.Hash,
-				Amount: update.Delta.Usdc,
-				Fee:    update.Delta.Fee,
-				Nonce:  update.Delta.Nonce,
+				Amount: delta.Usdc,
+				Fee:    delta.Fee,
+				Nonce:  delta.Nonce,
 			}
 			withdrawals = append(withdrawals, withrawal)
 		}
@@ -363,10 +780,10 @@ func (api *InfoAPI) GetDeposits(address string) (*[]Deposit, error) {
 	}
 	var deposits []Deposit
 	for _, update := range *updates {
-		if update.Delta.Type == "deposit" {
+		if delta, ok := update.Delta.(*DepositDelta); ok {
 			deposit := Deposit{
 				Hash:   update.Hash,
-				Amount: update.Delta.Usdc,
+				Amount: delta.Usdc,
 				Time:   update.Time,
 			}
 			deposits = append(deposits, deposit)
@@ -1,8 +1,14 @@
 package hyperliquid
 
 import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 )
 
 func GetInfoAPI() *InfoAPI {
@@ -20,6 +26,29 @@ func GetInfoAPI() *InfoAPI {
 	return api
 }
 
+// GetMockInfoAPI returns an InfoAPI that sends its requests to handler instead of the real API
+func GetMockInfoAPI[T any](t *testing.T, handler func(request T) any) *InfoAPI {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request T
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("Decode() error = %v", err)
+		}
+		json.NewEncoder(w).Encode(handler(request))
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient(false)
+	client.baseUrl = server.URL
+	// Nonces and clock offset of the mock server must not leak into the process-wide manager
+	client.SetNonceManager(NewNonceManager(NewMemoryNonceStore()))
+	if GLOBAL_DEBUG {
+		client.SetDebugActive()
+	}
+	return &InfoAPI{
+		Client:       *client,
+		baseEndpoint: "/info",
+	}
+}
+
 func TestInfoAPI_AccountAddress(t *testing.T) {
 	api := GetInfoAPI()
 	address := api.AccountAddress()
@@ -68,59 +97,328 @@ func TestInfoAPI_GetAccountFills(t *testing.T) {
 	}
 	res0 := (*res)[0]
 	t.Logf("res0 = %+v", res0)
-	if res0.Px == 0 {
+	if res0.Px.IsZero() {
 		t.Errorf("res0.Px = %v, want > %v", res0.Px, 0)
 	}
-	if res0.Sz == 0 {
+	if res0.Sz.IsZero() {
 		t.Errorf("res0.Sz = %v, want > %v", res0.Sz, 0)
 	}
-	if res0.Fee == 0 {
+	if res0.Fee.IsZero() {
 		t.Errorf("res0.Fee = %v, want > %v", res0.Fee, 0)
 	}
 	t.Logf("GetAccountFills() = %v", res)
 }
 
+func TestInfoAPI_GetAccountFillsByTime(t *testing.T) {
+	api := GetInfoAPI()
+	startTime, endTime := GetDefaultTimeRange()
+	count := 0
+	for fill, err := range api.GetAccountFillsByTime(startTime, endTime, false) {
+		if err != nil {
+			t.Fatalf("GetAccountFillsByTime() error = %v", err)
+		}
+		if fill.Time < startTime || fill.Time > endTime {
+			t.Errorf("fill.Time = %v, want in [%v, %v]", fill.Time, startTime, endTime)
+		}
+		count++
+	}
+	t.Logf("GetAccountFillsByTime() count = %v", count)
+}
+
+func TestInfoAPI_GetUserFillsByTimePagination(t *testing.T) {
+	// 4500 fills, two per millisecond, served by a mock that caps pages like the API does
+	var all []map[string]any
+	for i := 0; i < 4500; i++ {
+		all = append(all, map[string]any{"tid": i, "time": 1000 + i/2, "px": "1.0", "sz": "1.0"})
+	}
+	requests := 0
+	api := GetMockInfoAPI(t, func(request InfoRequest) any {
+		requests++
+		if request.Typez != "userFillsByTime" {
+			t.Errorf("Typez = %v, want %v", request.Typez, "userFillsByTime")
+		}
+		page := []map[string]any{}
+		for _, fill := range all {
+			if int64(fill["time"].(int)) >= request.StartTime && len(page) < USER_FILLS_BY_TIME_LIMIT {
+				page = append(page, fill)
+			}
+		}
+		return page
+	})
+	var tids []int64
+	for fill, err := range api.GetUserFillsByTime("0x0", 1000, 0, false) {
+		if err != nil {
+			t.Fatalf("GetUserFillsByTime() error = %v", err)
+		}
+		tids = append(tids, fill.Tid)
+	}
+	if len(tids) != len(all) {
+		t.Fatalf("GetUserFillsByTime() count = %v, want %v", len(tids), len(all))
+	}
+	for i, tid := range tids {
+		if tid != int64(i) {
+			t.Fatalf("tids[%d] = %v, want %v", i, tid, i)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("requests = %v, want %v", requests, 3)
+	}
+}
+
+func TestInfoAPI_GetUserFillsByTimeTruncated(t *testing.T) {
+	// More fills in one millisecond than a page holds
+	api := GetMockInfoAPI(t, func(request InfoRequest) any {
+		page := make([]map[string]any, USER_FILLS_BY_TIME_LIMIT)
+		for i := range page {
+			page[i] = map[string]any{"tid": i, "time": 1000, "px": "1.0", "sz": "1.0"}
+		}
+		return page
+	})
+	count := 0
+	var err error
+	for _, err = range api.GetUserFillsByTime("0x0", 1000, 0, false) {
+		if err != nil {
+			break
+		}
+		count++
+	}
+	if count != USER_FILLS_BY_TIME_LIMIT || !errors.Is(err, ErrFillsTruncated) {
+		t.Errorf("GetUserFillsByTime() = %d fills, error %v, want %d fills and ErrFillsTruncated", count, err, USER_FILLS_BY_TIME_LIMIT)
+	}
+}
+
 func TestInfoAPI_GetAccountRateLimits(t *testing.T) {
 	api := GetInfoAPI()
 	res, err := api.GetAccountRateLimits()
 	if err != nil {
 		t.Errorf("GetAccountRateLimits() error = %v", err)
 	}
-	if res.CumVlm == 0 {
+	if res.CumVlm.IsZero() {
 		t.Errorf("GetAccountRateLimits() len = %v, want > %v", res.CumVlm, 0)
 	}
 
 	t.Logf("GetAccountRateLimits() = %v", res)
 }
 
+func TestInfoAPI_GetAccountFees(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetAccountFees()
+	if err != nil {
+		t.Fatalf("GetAccountFees() error = %v", err)
+	}
+	if !res.UserCrossRate.IsPositive() {
+		t.Errorf("res.UserCrossRate = %v, want > %v", res.UserCrossRate, 0)
+	}
+	if len(res.FeeSchedule.Tiers.Vip) == 0 {
+		t.Errorf("res.FeeSchedule.Tiers.Vip len = %v, want > %v", res.FeeSchedule.Tiers.Vip, 0)
+	}
+	t.Logf("GetAccountFees() = %+v", res)
+}
+
+func TestInfoAPI_GetUserFeesEstimateFee(t *testing.T) {
+	api := GetMockInfoAPI(t, func(request InfoRequest) any {
+		return json.RawMessage(`{"dailyUserVlm":[{"date":"2025-05-23","userCross":"1500.5","userAdd":"0.0","exchange":"2852367.07"}],
+			"feeSchedule":{"cross":"0.00045","add":"0.00015","spotCross":"0.0007","spotAdd":"0.0004",
+			"tiers":{"vip":[{"ntlCutoff":"5000000.0","cross":"0.0004","add":"0.00012","spotCross":"0.0006","spotAdd":"0.0003"}],
+			"mm":[{"makerFractionCutoff":"0.005","add":"-0.00001"}]},"referralDiscount":"0.04",
+			"stakingDiscountTiers":[{"bpsOfMaxSupply":"0.0","discount":"0.0"},{"bpsOfMaxSupply":"0.0001","discount":"0.05"}]},
+			"userCrossRate":"0.000315","userAddRate":"0.000105","userSpotCrossRate":"0.000525","userSpotAddRate":"0.000315",
+			"activeReferralDiscount":"0.0","trial":null,"feeTrialReward":"0.0","nextTrialAvailableTimestamp":null,
+			"stakingLink":null,"activeStakingDiscount":{"bpsOfMaxSupply":"0.0","discount":"0.0"}}`)
+	})
+	fees, err := api.GetUserFees("0x0")
+	if err != nil {
+		t.Fatalf("GetUserFees() error = %v", err)
+	}
+	if !fees.FeeSchedule.Tiers.Mm[0].Add.Equal(MustDecimal("-0.00001")) || !fees.DailyUserVlm[0].UserCross.Equal(MustDecimal("1500.5")) {
+		t.Errorf("GetUserFees() = %+v", fees)
+	}
+	order := OrderRequest{Coin: "ETH", Sz: MustDecimal("2"), LimitPx: MustDecimal("1000"), OrderType: OrderType{Limit: &LimitOrderType{Tif: TifIoc}}}
+	if fee := fees.EstimateFee(order, false); !fee.Equal(MustDecimal("0.63")) {
+		t.Errorf("EstimateFee(Ioc) = %s, want 0.63", fee)
+	}
+	order.OrderType.Limit.Tif = TifAlo
+	if fee := fees.EstimateFee(order, false); !fee.Equal(MustDecimal("0.21")) {
+		t.Errorf("EstimateFee(Alo) = %s, want 0.21", fee)
+	}
+	if fee := fees.EstimateFee(order, true); !fee.Equal(MustDecimal("0.63")) {
+		t.Errorf("EstimateFee(Alo, spot) = %s, want 0.63", fee)
+	}
+}
+
 func TestInfoAPI_GetL2BookSnapshot(t *testing.T) {
 	api := GetInfoAPI()
 	res, err := api.GetL2BookSnapshot("BTC")
 	if err != nil {
 		t.Errorf("GetL2BookSnapshot() error = %v", err)
 	}
-	if res.Levels[0][0].Px <= 0 {
+	if !res.Levels[0][0].Px.IsPositive() {
 		t.Errorf("res.Levels[0][0].Px = %v, want > %v", res.Levels[0][0].Px, 0)
 	}
 	t.Logf("GetL2BookSnapshot() = %v", res)
 }
 
+func TestInfoAPI_GetAggregatedL2BookSnapshot(t *testing.T) {
+	api := GetMockInfoAPI(t, func(request InfoRequest) any {
+		return json.RawMessage(`{"coin":"BTC","time":1754450974231,"levels":[
+			[{"px":"113370","sz":"7.6699","n":17},{"px":"113360","sz":"0.0005","n":1}],
+			[{"px":"113380","sz":"0.1","n":2}]]}`)
+	})
+	res, err := api.GetAggregatedL2BookSnapshot("BTC", 5, 2)
+	if err != nil {
+		t.Fatalf("GetAggregatedL2BookSnapshot() error = %v", err)
+	}
+	if len(res.Bids) != 2 || !res.Bids[0].Px.Equal(MustDecimal("113370")) || res.Bids[0].N != 17 {
+		t.Errorf("res.Bids = %+v", res.Bids)
+	}
+	if len(res.Asks) != 1 || res.Asks[0].Sz.String() != "0.1" {
+		t.Errorf("res.Asks = %+v", res.Asks)
+	}
+	if _, err := api.GetAggregatedL2BookSnapshot("BTC", 6, 0); err == nil {
+		t.Errorf("GetAggregatedL2BookSnapshot(nSigFigs=6) error = nil, want error")
+	}
+	if _, err := api.GetAggregatedL2BookSnapshot("BTC", 4, 2); err == nil {
+		t.Errorf("GetAggregatedL2BookSnapshot(nSigFigs=4, mantissa=2) error = nil, want error")
+	}
+}
+
 func TestInfoAPI_GetCandleSnapshot(t *testing.T) {
 	api := GetInfoAPI()
 	startTime, endTime := GetDefaultTimeRange()
-	res, err := api.GetCandleSnapshot("ETH", "1d", startTime, endTime)
+	res, err := api.GetCandleSnapshot("ETH", Candle1d, startTime, endTime)
 	if err != nil {
 		t.Errorf("GetCandleSnapshot() error = %v", err)
 	}
 	if len(*res) == 0 {
 		t.Errorf("GetCandleSnapshot() len = %v, want > %v", res, 0)
 	}
-	if (*res)[0].Open <= 0 {
+	if !(*res)[0].Open.IsPositive() {
 		t.Errorf("*res)[0].Open  = %v, want > %v", (*res)[0].Open, 0)
 	}
 	t.Logf("GetCandleSnapshot() = %v", res)
 }
 
+func TestInfoAPI_GetCandles(t *testing.T) {
+	hour := Candle1h.Duration().Milliseconds()
+	startTime := int64(1700000000000) / hour * hour
+	endTime := startTime + 12000*hour - 1
+	requests := 0
+	api := GetMockInfoAPI(t, func(request CandleSnapshotRequest) any {
+		requests++
+		if request.Req.EndTime-request.Req.StartTime >= CANDLE_SNAPSHOT_LIMIT*hour {
+			t.Errorf("request range = %v candles, want <= %v", (request.Req.EndTime-request.Req.StartTime)/hour, CANDLE_SNAPSHOT_LIMIT)
+		}
+		// Return the candle containing StartTime too, like the API does, and skip a missing hour
+		candles := []map[string]any{}
+		for openTime := request.Req.StartTime / hour * hour; openTime <= request.Req.EndTime; openTime += hour {
+			if openTime == startTime+100*hour {
+				continue
+			}
+			candles = append(candles, map[string]any{"t": openTime, "T": openTime + hour - 1, "s": request.Req.Coin,
+				"i": request.Req.Interval, "o": "1.0", "c": "1.0", "h": "1.0", "l": "1.0", "v": "1.0", "n": 1})
+		}
+		return candles
+	})
+	api.SetCandleCacheDir(t.TempDir())
+
+	res, err := api.GetCandles("BTC", Candle1h, startTime, endTime)
+	if err != nil {
+		t.Fatalf("GetCandles() error = %v", err)
+	}
+	if len(*res) != 12000-1 {
+		t.Errorf("GetCandles() len = %v, want %v", len(*res), 12000-1)
+	}
+	if requests != 3 {
+		t.Errorf("requests = %v, want %v", requests, 3)
+	}
+	gaps := CandleGaps(*res, Candle1h)
+	if len(gaps) != 1 || gaps[0] != (CandleGap{From: startTime + 99*hour, To: startTime + 101*hour}) {
+		t.Errorf("CandleGaps() = %+v, want one gap at candle 100", gaps)
+	}
+
+	// The second call is served from the cache
+	cached, err := api.GetCandles("BTC", Candle1h, startTime+hour, endTime-hour)
+	if err != nil {
+		t.Fatalf("GetCandles() error = %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("requests = %v, want %v", requests, 3)
+	}
+	if len(*cached) != 12000-3 || (*cached)[0].OpenTime != startTime+hour {
+		t.Errorf("GetCandles() cached len = %v, first = %v", len(*cached), (*cached)[0].OpenTime)
+	}
+
+	// The candles of testnet are not used on mainnet
+	api.isMainnet = true
+	if _, err := api.GetCandles("BTC", Candle1h, startTime+hour, endTime-hour); err != nil {
+		t.Fatalf("GetCandles() error = %v", err)
+	}
+	if requests == 3 {
+		t.Errorf("GetCandles() on mainnet was served from the testnet cache")
+	}
+
+	if _, err := api.GetCandles("BTC", CandleInterval("2m"), startTime, endTime); err == nil {
+		t.Errorf("GetCandles(2m) error = nil, want error")
+	}
+}
+
+func TestInfoAPI_GetCandlesOpenCandle(t *testing.T) {
+	hour := Candle1h.Duration().Milliseconds()
+	startTime := int64(1700000000000) / hour * hour
+	endTime := startTime + 10*hour + 30*60*1000
+	now := time.UnixMilli(endTime)
+	requests := 0
+	api := GetMockInfoAPI(t, func(request CandleSnapshotRequest) any {
+		requests++
+		candles := []map[string]any{}
+		for openTime := request.Req.StartTime / hour * hour; openTime <= request.Req.EndTime; openTime += hour {
+			// The candle in progress has the last price so far
+			close := "1.0"
+			if openTime+hour-1 >= now.UnixMilli() {
+				close = "0.5"
+			}
+			candles = append(candles, map[string]any{"t": openTime, "T": openTime + hour - 1, "s": request.Req.Coin,
+				"i": request.Req.Interval, "o": "1.0", "c": close, "h": "1.0", "l": "1.0", "v": "1.0", "n": 1})
+		}
+		return candles
+	})
+	api.SetCandleCacheDir(t.TempDir())
+	api.SetClock(func() time.Time { return now })
+
+	// endTime is in the candle still in progress
+	res, err := api.GetCandles("BTC", Candle1h, startTime, endTime)
+	if err != nil {
+		t.Fatalf("GetCandles() error = %v", err)
+	}
+	if len(*res) != 11 || !(*res)[10].Close.Equal(MustDecimal("0.5")) {
+		t.Fatalf("GetCandles() = %d candles, want 11 with the last one in progress", len(*res))
+	}
+
+	// Once it's closed, the candle is fetched again instead of being skipped as cached
+	now = now.Add(time.Hour)
+	res, err = api.GetCandles("BTC", Candle1h, startTime, endTime)
+	if err != nil {
+		t.Fatalf("GetCandles() error = %v", err)
+	}
+	if requests != 2 || len(*res) != 11 || !(*res)[10].Close.Equal(MustDecimal("1")) {
+		t.Errorf("GetCandles() after close = %d candles after %d requests, want the closed candle after 2 requests", len(*res), requests)
+	}
+	if _, err := api.GetCandles("BTC", Candle1h, startTime, endTime); err != nil || requests != 2 {
+		t.Errorf("GetCandles() of closed candles made %d requests (error %v), want them cached", requests, err)
+	}
+}
+
+func TestInfoAPI_CandleIntervalNextOpenTime(t *testing.T) {
+	jan := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	feb := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	if next := Candle1M.NextOpenTime(jan); next != feb {
+		t.Errorf("Candle1M.NextOpenTime() = %v, want %v", next, feb)
+	}
+	if next := Candle15m.NextOpenTime(jan); next != jan+15*60*1000 {
+		t.Errorf("Candle15m.NextOpenTime() = %v, want %v", next, jan+15*60*1000)
+	}
+}
+
 func TestInfoAPI_GetMeta(t *testing.T) {
 	api := GetInfoAPI()
 	res, err := api.GetMeta()
@@ -133,21 +431,97 @@ func TestInfoAPI_GetMeta(t *testing.T) {
 	}
 }
 
+func TestInfoAPI_GetMetaAndAssetCtxs(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetMetaAndAssetCtxs()
+	if err != nil {
+		t.Fatalf("GetMetaAndAssetCtxs() error = %v", err)
+	}
+	if len(*res) == 0 {
+		t.Fatalf("GetMetaAndAssetCtxs() len = %v, want > %v", res, 0)
+	}
+	if !(*res)[0].Context.MarkPx.IsPositive() {
+		t.Errorf("(*res)[0].Context.MarkPx = %v, want > %v", (*res)[0].Context.MarkPx, 0)
+	}
+	t.Logf("GetMetaAndAssetCtxs() = %+v", (*res)[0])
+}
+
+func TestInfoAPI_GetMetaAndAssetCtxsDecode(t *testing.T) {
+	api := GetMockInfoAPI(t, func(request InfoRequest) any {
+		return json.RawMessage(`[{"universe":[{"name":"BTC","szDecimals":5,"maxLeverage":50},{"name":"ETH","szDecimals":4,"maxLeverage":50}]},
+			[{"dayNtlVlm":"1169046.29406","funding":"0.0000125","impactPxs":["14.3047","14.3444"],"markPx":"14.3161",
+			"midPx":"14.314","openInterest":"688.11","oraclePx":"14.32","premium":"0.00031774","prevDayPx":"15.322"},
+			{"dayNtlVlm":"0.0","funding":"0.0","impactPxs":null,"markPx":"3000.1","midPx":null,"openInterest":"0.0",
+			"oraclePx":"3000.0","premium":null,"prevDayPx":"2900.0"}]]`)
+	})
+	res, err := api.GetMetaAndAssetCtxs()
+	if err != nil {
+		t.Fatalf("GetMetaAndAssetCtxs() error = %v", err)
+	}
+	btc, eth := (*res)[0], (*res)[1]
+	if btc.Asset.Name != "BTC" || !btc.Context.Funding.Equal(MustDecimal("0.0000125")) || !btc.Context.ImpactPxs[1].Equal(MustDecimal("14.3444")) {
+		t.Errorf("(*res)[0] = %+v", btc)
+	}
+	if eth.AssetId != 1 || !eth.Context.MarkPx.Equal(MustDecimal("3000.1")) || !eth.Context.MidPx.IsZero() || eth.Context.ImpactPxs != nil {
+		t.Errorf("(*res)[1] = %+v", eth)
+	}
+}
+
 func TestInfoAPI_GetUserState(t *testing.T) {
 	api := GetInfoAPI()
 	res, err := api.GetAccountState()
 	if err != nil {
 		t.Errorf("GetUserState() error = %v", err)
 	}
-	if res.Withdrawable == 0 {
+	if res.Withdrawable.IsZero() {
 		t.Errorf("GetUserState.Withdrawable = %v, want > %v", res.Withdrawable, 0)
 	}
-	if res.CrossMarginSummary.AccountValue == 0 {
+	if res.CrossMarginSummary.AccountValue.IsZero() {
 		t.Errorf("GetUserState.AccountValue = %v, want > %v", res.CrossMarginSummary.AccountValue, 0)
 	}
 	t.Logf("GetUserState() = %v", res)
 }
 
+func TestInfoAPI_GetAccountActiveAssetData(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetAccountActiveAssetData("ETH")
+	if err != nil {
+		t.Fatalf("GetAccountActiveAssetData() error = %v", err)
+	}
+	if res.Leverage.Value == 0 {
+		t.Errorf("res.Leverage.Value = %v, want > %v", res.Leverage.Value, 0)
+	}
+	t.Logf("GetAccountActiveAssetData() = %+v", res)
+}
+
+func TestInfoAPI_GetAccountPortfolio(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetAccountPortfolio()
+	if err != nil {
+		t.Fatalf("GetAccountPortfolio() error = %v", err)
+	}
+	if len(res.AllTime.AccountValueHistory) == 0 {
+		t.Errorf("res.AllTime.AccountValueHistory len = %v, want > %v", res.AllTime.AccountValueHistory, 0)
+	}
+	t.Logf("GetAccountPortfolio().AllTime.Stats() = %+v", res.AllTime.Stats())
+}
+
+func TestInfoAPI_DecodePortfolio(t *testing.T) {
+	data := `[["day",{"accountValueHistory":[[1741886630493,"100.5"]],"pnlHistory":[[1741886630493,"1.5"]],"vlm":"10.0"}],
+		["perpAllTime",{"accountValueHistory":[],"pnlHistory":[[1741886630493,"-2.25"]],"vlm":"0.0"}],
+		["unknownWindow",{}]]`
+	var res Portfolio
+	if err := json.Unmarshal([]byte(data), &res); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if res.Day.AccountValueHistory[0].Value != 100.5 || res.Day.Vlm != 10 {
+		t.Errorf("res.Day = %+v", res.Day)
+	}
+	if res.PerpAllTime.PnlHistory[0] != (TimeValue{Time: 1741886630493, Value: -2.25}) {
+		t.Errorf("res.PerpAllTime = %+v", res.PerpAllTime)
+	}
+}
+
 func TestInfoAPI_GetAccountOpenOrders(t *testing.T) {
 	api := GetInfoAPI()
 	res, err := api.GetAccountOpenOrders()
@@ -160,6 +534,61 @@ func TestInfoAPI_GetAccountOpenOrders(t *testing.T) {
 	t.Logf("GetAccountOpenOrders() = %v", res)
 }
 
+func TestInfoAPI_GetAccountFrontendOpenOrders(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetAccountFrontendOpenOrders()
+	if err != nil {
+		t.Errorf("GetAccountFrontendOpenOrders() error = %v", err)
+	}
+	for _, order := range *res {
+		if order.OrderType == "" {
+			t.Errorf("order.OrderType = %v, want not empty", order.OrderType)
+		}
+	}
+	t.Logf("GetAccountFrontendOpenOrders() = %+v", res)
+}
+
+func TestInfoAPI_GetAccountHistoricalOrders(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetAccountHistoricalOrders()
+	if err != nil {
+		t.Errorf("GetAccountHistoricalOrders() error = %v", err)
+	}
+	if len(*res) == 0 {
+		t.Errorf("GetAccountHistoricalOrders() len = %v, want > %v", res, 0)
+	}
+	for _, order := range *res {
+		if order.Status == "" {
+			t.Errorf("order.Status = %v, want not empty", order.Status)
+		}
+	}
+	t.Logf("GetAccountHistoricalOrders() = %+v", res)
+}
+
+func TestInfoAPI_DecodeHistoricalOrders(t *testing.T) {
+	data := `[{"order":{"coin":"ETH","side":"A","limitPx":"2412.7","sz":"0.0","oid":1,"timestamp":1724361546645,
+		"triggerCondition":"N/A","isTrigger":false,"triggerPx":"0.0","isPositionTpsl":false,"reduceOnly":true,
+		"orderType":"Market","origSz":"0.0076","tif":"FrontendMarket","cloid":null,
+		"children":[{"coin":"ETH","side":"A","limitPx":"2600.0","sz":"0.0076","oid":2,"timestamp":1724361546645,
+		"triggerCondition":"Price above 2600","isTrigger":true,"triggerPx":"2600.0","isPositionTpsl":false,
+		"reduceOnly":true,"orderType":"Take Profit Market","origSz":"0.0076","tif":null,"cloid":null,"children":[]}]},
+		"status":"filled","statusTimestamp":1724361546645}]`
+	var res []HistoricalOrder
+	if err := json.Unmarshal([]byte(data), &res); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if res[0].Status != OrderStatusFilled {
+		t.Errorf("Status = %v, want %v", res[0].Status, OrderStatusFilled)
+	}
+	if len(res[0].Order.Children) != 1 {
+		t.Fatalf("len(Children) = %v, want %v", len(res[0].Order.Children), 1)
+	}
+	child := res[0].Order.Children[0]
+	if !child.IsTrigger || !child.TriggerPx.Equal(MustDecimal("2600")) || child.OrderType != "Take Profit Market" {
+		t.Errorf("Children[0] = %+v, want trigger order at 2600", child)
+	}
+}
+
 func TestInfoAPI_GetAccountFundingUpdates(t *testing.T) {
 	api := GetInfoAPI()
 	startTime, endTime := GetDefaultTimeRange()
@@ -186,6 +615,47 @@ func TestInfoAPI_GetHistoricalFundingRates(t *testing.T) {
 	t.Logf("GetHistoricalFundingRates() = %v", res)
 }
 
+func TestInfoAPI_GetPredictedFundings(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetPredictedFundings()
+	if err != nil {
+		t.Fatalf("GetPredictedFundings() error = %v", err)
+	}
+	if _, ok := res.Venue("BTC", VenueHlPerp); !ok {
+		t.Errorf("GetPredictedFundings() doesnt return %v for %v", VenueHlPerp, "BTC")
+	}
+	t.Logf("GetPredictedFundings()[BTC] = %+v", (*res)["BTC"])
+}
+
+func TestInfoAPI_DecodePredictedFundings(t *testing.T) {
+	data := `[["AVAX",[["BinPerp",{"fundingRate":"0.0001","nextFundingTime":1733961600000}],
+		["HlPerp",{"fundingRate":"0.0000125","nextFundingTime":1733958000000}],["BybitPerp",null]]]]`
+	var res PredictedFundings
+	if err := json.Unmarshal([]byte(data), &res); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if len(res["AVAX"]) != 2 {
+		t.Fatalf("res[AVAX] = %+v, want 2 venues", res["AVAX"])
+	}
+	hl, _ := res.Venue("AVAX", VenueHlPerp)
+	bin, _ := res.Venue("AVAX", VenueBinPerp)
+	if math.Abs(hl.AnnualizedRate()-0.1095) > 1e-12 {
+		t.Errorf("HlPerp.AnnualizedRate() = %v, want %v", hl.AnnualizedRate(), 0.1095)
+	}
+	if math.Abs(bin.AnnualizedRate()-0.1095) > 1e-12 {
+		t.Errorf("BinPerp.AnnualizedRate() = %v, want %v", bin.AnnualizedRate(), 0.1095)
+	}
+}
+
+func TestInfoAPI_GetPerpsAtOpenInterestCap(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetPerpsAtOpenInterestCap()
+	if err != nil {
+		t.Errorf("GetPerpsAtOpenInterestCap() error = %v", err)
+	}
+	t.Logf("GetPerpsAtOpenInterestCap() = %v", res)
+}
+
 func TestInfoAPI_GetAccountNonFundingUpdates(t *testing.T) {
 	api := GetInfoAPI()
 	startTime, endTime := GetDefaultTimeRange()
@@ -198,40 +668,72 @@ func TestInfoAPI_GetAccountNonFundingUpdates(t *testing.T) {
 	}
 	// find first deposit
 	for _, update := range *res {
-		if update.Delta.Type == "deposit" {
+		switch delta := update.Delta.(type) {
+		case *DepositDelta:
 			// check that usdc is in the deposit
-			if update.Delta.Usdc == 0 {
-				t.Errorf("update.Delta.Usdc = %v, want > %v", update.Delta.Amount, 0)
+			if delta.Usdc.IsZero() {
+				t.Errorf("delta.Usdc = %v, want > %v", delta.Usdc, 0)
 			}
-		}
-		if update.Delta.Type == "withdrawal" {
-			if update.Delta.Usdc == 0 {
-				t.Errorf("update.Delta.Usdc = %v, want > %v", update.Delta.Amount, 0)
+		case *WithdrawDelta:
+			if delta.Usdc.IsZero() {
+				t.Errorf("delta.Usdc = %v, want > %v", delta.Usdc, 0)
 			}
-			if update.Delta.Nonce == 0 {
-				t.Errorf("update.Delta.Nonce = %v, want > %v", update.Delta.Nonce, 0)
+			if delta.Nonce == 0 {
+				t.Errorf("delta.Nonce = %v, want > %v", delta.Nonce, 0)
 			}
-			if update.Delta.Fee == 0 {
-				t.Errorf("update.Delta.Fee = %v, want > %v", update.Delta.Fee, 0)
+			if delta.Fee.IsZero() {
+				t.Errorf("delta.Fee = %v, want > %v", delta.Fee, 0)
 			}
-		}
-		if update.Delta.Type == "spotGenesis" {
-			if update.Delta.Token == "" {
-				t.Errorf("update.Delta.Token = %v", update.Delta.Amount)
+		case *SpotGenesisDelta:
+			if delta.Token == "" {
+				t.Errorf("delta.Token = %v", delta.Token)
 			}
-			if update.Delta.Amount == 0 {
-				t.Errorf("update.Delta.Amount = %v, want > %v", update.Delta.Amount, 0)
+			if delta.Amount.IsZero() {
+				t.Errorf("delta.Amount = %v, want > %v", delta.Amount, 0)
 			}
-		}
-		if update.Delta.Type == "accountClassTransfer" {
-			if update.Delta.Usdc == 0 {
-				t.Errorf("update.Delta.Usdc = %v, want > %v", update.Delta.Amount, 0)
+		case *AccountClassTransferDelta:
+			if delta.Usdc.IsZero() {
+				t.Errorf("delta.Usdc = %v, want > %v", delta.Usdc, 0)
 			}
 		}
 	}
 	t.Logf("GetAccountNonFundingUpdates() = %v", res)
 }
 
+func TestInfoAPI_DecodeNonFundingUpdates(t *testing.T) {
+	data := `[{"time":1,"hash":"0x1","delta":{"type":"deposit","usdc":"100.5"}},
+		{"time":2,"hash":"0x2","delta":{"type":"withdraw","usdc":"20.0","nonce":1712345678901,"fee":"1.0"}},
+		{"time":3,"hash":"0x3","delta":{"type":"accountClassTransfer","usdc":"5.0","toPerp":true}},
+		{"time":4,"hash":"0x4","delta":{"type":"liquidation","liquidatedNtlPos":"1500.0","accountValue":"30.1",
+			"leverageType":"Cross","liquidatedPositions":[{"coin":"ETH","szi":"-0.5"}]}},
+		{"time":5,"hash":"0x5","delta":{"type":"spotTransfer","token":"HYPE","amount":"2.5","usdcValue":"62.5",
+			"user":"0xa","destination":"0xb","fee":"0.0","nativeTokenFee":"0.0","nonce":7}},
+		{"time":6,"hash":"0x6","delta":{"type":"somethingNew","foo":"bar"}}]`
+	var res []NonFundingUpdate
+	if err := json.Unmarshal([]byte(data), &res); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if delta, ok := res[0].Delta.(*DepositDelta); !ok || !delta.Usdc.Equal(MustDecimal("100.5")) {
+		t.Errorf("res[0].Delta = %+v, want deposit of 100.5", res[0].Delta)
+	}
+	if delta, ok := res[1].Delta.(*WithdrawDelta); !ok || delta.Nonce != 1712345678901 || !delta.Fee.Equal(MustDecimal("1")) {
+		t.Errorf("res[1].Delta = %+v, want withdraw", res[1].Delta)
+	}
+	if delta, ok := res[2].Delta.(*AccountClassTransferDelta); !ok || !delta.ToPerp {
+		t.Errorf("res[2].Delta = %+v, want transfer to perp", res[2].Delta)
+	}
+	if delta, ok := res[3].Delta.(*LiquidationDelta); !ok || !delta.LiquidatedPositions[0].Szi.Equal(MustDecimal("-0.5")) {
+		t.Errorf("res[3].Delta = %+v, want liquidation", res[3].Delta)
+	}
+	if delta, ok := res[4].Delta.(*SpotTransferDelta); !ok || delta.Token != "HYPE" || !delta.Amount.Equal(MustDecimal("2.5")) {
+		t.Errorf("res[4].Delta = %+v, want spot transfer", res[4].Delta)
+	}
+	unknown, ok := res[5].Delta.(*UnknownDelta)
+	if !ok || unknown.DeltaType() != "somethingNew" || string(unknown.Raw) != `{"type":"somethingNew","foo":"bar"}` {
+		t.Errorf("res[5].Delta = %+v, want unknown delta", res[5].Delta)
+	}
+}
+
 func TestInfoAPI_GetAccountWithdrawals(t *testing.T) {
 	api := GetInfoAPI()
 	res, err := api.GetAccountWithdrawals()
@@ -242,7 +744,7 @@ func TestInfoAPI_GetAccountWithdrawals(t *testing.T) {
 		t.Errorf("GetAccountWithdrawals() len = %v, want > %v", res, 0)
 	}
 	for _, withdrawal := range *res {
-		if withdrawal.Amount == 0 {
+		if withdrawal.Amount.IsZero() {
 			t.Errorf("withdrawal.Amount = %v, want > %v", withdrawal.Amount, 0)
 		}
 	}
@@ -259,7 +761,7 @@ func TestInfoAPI_GetAccountDeposits(t *testing.T) {
 		t.Errorf("GetAccountDeposits() len = %v, want > %v", res, 0)
 	}
 	for _, deposit := range *res {
-		if deposit.Amount == 0 {
+		if deposit.Amount.IsZero() {
 			t.Errorf("deposit.Amount = %v, want > %v", deposit.Amount, 0)
 		}
 	}
@@ -341,6 +843,55 @@ func TestInfoAPI_GetAllSpotPrices(t *testing.T) {
 	t.Logf("GetAllSpotPrices() = %+v", res)
 }
 
+func TestInfoAPI_GetSpotMetaAndAssetCtxs(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetSpotMetaAndAssetCtxs()
+	if err != nil {
+		t.Fatalf("GetSpotMetaAndAssetCtxs() error = %v", err)
+	}
+	if len(*res) == 0 {
+		t.Fatalf("GetSpotMetaAndAssetCtxs() len = %v, want > %v", res, 0)
+	}
+	if (*res)[0].Name != "PURR/USDC" {
+		t.Errorf("(*res)[0].Name = %v, want %v", (*res)[0].Name, "PURR/USDC")
+	}
+	t.Logf("GetSpotMetaAndAssetCtxs() = %+v", (*res)[0])
+}
+
+func TestInfoAPI_GetSpotMetaAndAssetCtxsDecode(t *testing.T) {
+	api := GetMockInfoAPI(t, func(request InfoRequest) any {
+		return json.RawMessage(`[{"universe":[{"tokens":[1,0],"name":"PURR/USDC","index":0,"isCanonical":true},
+			{"tokens":[150,0],"name":"@107","index":107,"isCanonical":false}],
+			"tokens":[{"name":"USDC","szDecimals":8,"weiDecimals":8,"index":0},{"name":"PURR","szDecimals":0,"weiDecimals":5,"index":1},
+			{"name":"HYPE","szDecimals":2,"weiDecimals":8,"index":150}]},
+			[{"dayNtlVlm":"8906.0","markPx":"0.14","midPx":"0.20926500","prevDayPx":"0.20432","circulatingSupply":"851681534.05",
+			"coin":"PURR/USDC","totalSupply":"999999999.0","dayBaseVlm":"44180.0"},
+			{"dayNtlVlm":"123456.7","markPx":"25.3","midPx":null,"prevDayPx":"24.9","circulatingSupply":"333000000.0",
+			"coin":"@107","totalSupply":"1000000000.0","dayBaseVlm":"4900.0"}]]`)
+	})
+	res, err := api.GetSpotMetaAndAssetCtxs()
+	if err != nil {
+		t.Fatalf("GetSpotMetaAndAssetCtxs() error = %v", err)
+	}
+	hype := (*res)[1]
+	if hype.Coin != "@107" || hype.Name != "HYPE/USDC" || hype.Base.SzDecimals != 2 {
+		t.Errorf("(*res)[1] = %+v, want HYPE/USDC", hype)
+	}
+	if !hype.Context.MarkPx.Equal(MustDecimal("25.3")) || !hype.Context.CirculatingSupply.Equal(MustDecimal("333000000")) || !hype.Context.DayNtlVlm.Equal(MustDecimal("123456.7")) {
+		t.Errorf("(*res)[1].Context = %+v", hype.Context)
+	}
+	prices, err := api.GetAllSpotPrices()
+	if err != nil {
+		t.Fatalf("GetAllSpotPrices() error = %v", err)
+	}
+	if (*prices)["PURR/USDC"] != "0.20926500" {
+		t.Errorf("GetAllSpotPrices()[PURR/USDC] = %v, want %v", (*prices)["PURR/USDC"], "0.20926500")
+	}
+	if price, ok := (*prices)["@107"]; !ok || price != "" {
+		t.Errorf("GetAllSpotPrices()[@107] = %q, %v, want an empty price for the pair without mid price", price, ok)
+	}
+}
+
 func TestInfoAPI_GetSpotMarketPx(t *testing.T) {
 	api := GetInfoAPI()
 	res, err := api.GetSpotMarketPx("HYPE")
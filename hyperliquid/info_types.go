@@ -1,13 +1,22 @@
 package hyperliquid
 
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // Base request for /info
 type InfoRequest struct {
-	User      string `json:"user,omitempty"`
-	Typez     string `json:"type"`
-	Oid       string `json:"oid,omitempty"`
-	Coin      string `json:"coin,omitempty"`
-	StartTime int64  `json:"startTime,omitempty"`
-	EndTime   int64  `json:"endTime,omitempty"`
+	User            string `json:"user,omitempty"`
+	Typez           string `json:"type"`
+	Oid             string `json:"oid,omitempty"`
+	Coin            string `json:"coin,omitempty"`
+	StartTime       int64  `json:"startTime,omitempty"`
+	EndTime         int64  `json:"endTime,omitempty"`
+	AggregateByTime bool   `json:"aggregateByTime,omitempty"`
 }
 
 type UserStateRequest struct {
@@ -20,11 +29,12 @@ type Asset struct {
 	SzDecimals   int    `json:"szDecimals"`
 	MaxLeverage  int    `json:"maxLeverage"`
 	OnlyIsolated bool   `json:"onlyIsolated"`
+	IsDelisted   bool   `json:"isDelisted,omitempty"`
 }
 
 type UserState struct {
-	Withdrawable               float64         `json:"withdrawable,string"`
-	CrossMaintenanceMarginUsed float64         `json:"crossMaintenanceMarginUsed,string"`
+	Withdrawable               Decimal         `json:"withdrawable"`
+	CrossMaintenanceMarginUsed Decimal         `json:"crossMaintenanceMarginUsed"`
 	AssetPositions             []AssetPosition `json:"assetPositions"`
 	CrossMarginSummary         MarginSummary   `json:"crossMarginSummary"`
 	MarginSummary              MarginSummary   `json:"marginSummary"`
@@ -38,19 +48,19 @@ type AssetPosition struct {
 
 type Position struct {
 	Coin           string   `json:"coin"`
-	EntryPx        float64  `json:"entryPx,string"`
+	EntryPx        Decimal  `json:"entryPx"`
 	Leverage       Leverage `json:"leverage"`
-	LiquidationPx  float64  `json:"liquidationPx,string"`
-	MarginUsed     float64  `json:"marginUsed,string"`
-	PositionValue  float64  `json:"positionValue,string"`
-	ReturnOnEquity float64  `json:"returnOnEquity,string"`
-	Szi            float64  `json:"szi,string"`
-	UnrealizedPnl  float64  `json:"unrealizedPnl,string"`
+	LiquidationPx  Decimal  `json:"liquidationPx"`
+	MarginUsed     Decimal  `json:"marginUsed"`
+	PositionValue  Decimal  `json:"positionValue"`
+	ReturnOnEquity Decimal  `json:"returnOnEquity"`
+	Szi            Decimal  `json:"szi"`
+	UnrealizedPnl  Decimal  `json:"unrealizedPnl"`
 	MaxLeverage    int      `json:"maxLeverage"`
 	CumFunding     struct {
-		AllTime   float64 `json:"allTime,string"`
-		SinceOpne float64 `json:"sinceOpen,string"`
-		SinceChan float64 `json:"sinceChange,string"`
+		AllTime   Decimal `json:"allTime"`
+		SinceOpne Decimal `json:"sinceOpen"`
+		SinceChan Decimal `json:"sinceChange"`
 	} `json:"cumFunding"`
 }
 
@@ -68,28 +78,96 @@ type SpotAssetPosition struct {
 	*/
 	Coin     string  `json:"coin"`
 	Token    int     `json:"token"`
-	Hold     float64 `json:"hold,string"`
-	Total    float64 `json:"total,string"`
-	EntryNtl float64 `json:"entryNtl,string"`
+	Hold     Decimal `json:"hold"`
+	Total    Decimal `json:"total"`
+	EntryNtl Decimal `json:"entryNtl"`
 }
 
 type Order struct {
-	Children         []any   `json:"children,omitempty"`
+	Children         []Order `json:"children,omitempty"`
 	Cloid            string  `json:"cloid,omitempty"`
 	Coin             string  `json:"coin"`
 	IsPositionTpsl   bool    `json:"isPositionTpsl,omitempty"`
 	IsTrigger        bool    `json:"isTrigger,omitempty"`
-	LimitPx          float64 `json:"limitPx,string,omitempty"`
+	LimitPx          Decimal `json:"limitPx"`
 	Oid              int64   `json:"oid"`
 	OrderType        string  `json:"orderType,omitempty"`
-	OrigSz           float64 `json:"origSz,string,omitempty"`
+	OrigSz           Decimal `json:"origSz"`
 	ReduceOnly       bool    `json:"reduceOnly,omitempty"`
 	Side             string  `json:"side"`
-	Sz               float64 `json:"sz,string,omitempty"`
+	Sz               Decimal `json:"sz"`
 	Tif              string  `json:"tif,omitempty"`
 	Timestamp        int64   `json:"timestamp"`
 	TriggerCondition string  `json:"triggerCondition,omitempty"`
-	TriggerPx        float64 `json:"triggerPx,string,omitempty"`
+	TriggerPx        Decimal `json:"triggerPx"`
+}
+
+// Order status as reported by historicalOrders, orderStatus and the orderUpdates websocket channel
+type OrderStatus string
+
+const (
+	OrderStatusOpen      OrderStatus = "open"
+	OrderStatusFilled    OrderStatus = "filled"
+	OrderStatusCanceled  OrderStatus = "canceled"
+	OrderStatusTriggered OrderStatus = "triggered"
+	OrderStatusRejected  OrderStatus = "rejected"
+
+	// Canceled by the exchange
+	OrderStatusMarginCanceled          OrderStatus = "marginCanceled"
+	OrderStatusVaultWithdrawalCanceled OrderStatus = "vaultWithdrawalCanceled"
+	OrderStatusOpenInterestCapCanceled OrderStatus = "openInterestCapCanceled"
+	OrderStatusSelfTradeCanceled       OrderStatus = "selfTradeCanceled"
+	OrderStatusReduceOnlyCanceled      OrderStatus = "reduceOnlyCanceled"
+	OrderStatusSiblingFilledCanceled   OrderStatus = "siblingFilledCanceled"
+	OrderStatusDelistedCanceled        OrderStatus = "delistedCanceled"
+	OrderStatusLiquidatedCanceled      OrderStatus = "liquidatedCanceled"
+	OrderStatusScheduledCancel         OrderStatus = "scheduledCancel"
+
+	// Rejected when placed
+	OrderStatusTickRejected                              OrderStatus = "tickRejected"
+	OrderStatusMinTradeNtlRejected                       OrderStatus = "minTradeNtlRejected"
+	OrderStatusPerpMarginRejected                        OrderStatus = "perpMarginRejected"
+	OrderStatusReduceOnlyRejected                        OrderStatus = "reduceOnlyRejected"
+	OrderStatusBadAloPxRejected                          OrderStatus = "badAloPxRejected"
+	OrderStatusIocCancelRejected                         OrderStatus = "iocCancelRejected"
+	OrderStatusBadTriggerPxRejected                      OrderStatus = "badTriggerPxRejected"
+	OrderStatusMarketOrderNoLiquidityRejected            OrderStatus = "marketOrderNoLiquidityRejected"
+	OrderStatusPositionIncreaseAtOpenInterestCapRejected OrderStatus = "positionIncreaseAtOpenInterestCapRejected"
+	OrderStatusPositionFlipAtOpenInterestCapRejected     OrderStatus = "positionFlipAtOpenInterestCapRejected"
+	OrderStatusTooAggressiveAtOpenInterestCapRejected    OrderStatus = "tooAggressiveAtOpenInterestCapRejected"
+	OrderStatusOpenInterestIncreaseRejected              OrderStatus = "openInterestIncreaseRejected"
+	OrderStatusInsufficientSpotBalanceRejected           OrderStatus = "insufficientSpotBalanceRejected"
+	OrderStatusOracleRejected                            OrderStatus = "oracleRejected"
+	OrderStatusPerpMaxPositionRejected                   OrderStatus = "perpMaxPositionRejected"
+)
+
+// IsCanceled returns true for all the canceled statuses, e.g. "canceled", "marginCanceled" or "scheduledCancel"
+func (status OrderStatus) IsCanceled() bool {
+	return strings.HasSuffix(strings.ToLower(string(status)), "canceled") || status == OrderStatusScheduledCancel
+}
+
+// IsRejected returns true for all the rejected statuses, e.g. "rejected" or "tickRejected"
+func (status OrderStatus) IsRejected() bool {
+	return strings.HasSuffix(strings.ToLower(string(status)), "rejected")
+}
+
+type HistoricalOrder struct {
+	Order           Order       `json:"order"`
+	Status          OrderStatus `json:"status"`
+	StatusTimestamp int64       `json:"statusTimestamp"`
+}
+
+// Request of orderStatus, Oid is an order id (int64) or a client order id (string)
+type OrderStatusRequest struct {
+	User  string `json:"user"`
+	Typez string `json:"type"`
+	Oid   any    `json:"oid"`
+}
+
+// Response of orderStatus, Status is "order" if the order was found, "unknownOid" otherwise
+type OrderStatusResponse struct {
+	Status string           `json:"status"`
+	Order  *HistoricalOrder `json:"order,omitempty"`
 }
 
 type Leverage struct {
@@ -97,30 +175,111 @@ type Leverage struct {
 	Value int    `json:"value"`
 }
 
+// Response of activeAssetData: trading limits of a user on a coin at its current leverage
+// MaxTradeSzs and AvailableToTrade are [buy, sell]
+type ActiveAssetData struct {
+	User             string     `json:"user"`
+	Coin             string     `json:"coin"`
+	Leverage         Leverage   `json:"leverage"`
+	MaxTradeSzs      [2]Decimal `json:"maxTradeSzs"`
+	AvailableToTrade [2]Decimal `json:"availableToTrade"`
+	MarkPx           Decimal    `json:"markPx"`
+}
+
+// UnmarshalJSON implements custom unmarshaling for ActiveAssetData.
+// MaxTradeSzs and AvailableToTrade must have exactly 2 elements.
+func (data *ActiveAssetData) UnmarshalJSON(raw []byte) error {
+	type Alias ActiveAssetData
+	aux := struct {
+		*Alias
+		MaxTradeSzs      []Decimal `json:"maxTradeSzs"`
+		AvailableToTrade []Decimal `json:"availableToTrade"`
+	}{Alias: (*Alias)(data)}
+	if err := json.Unmarshal(raw, &aux); err != nil {
+		return err
+	}
+	if len(aux.MaxTradeSzs) != 2 {
+		return fmt.Errorf("ActiveAssetData: invalid maxTradeSzs %v", aux.MaxTradeSzs)
+	}
+	if len(aux.AvailableToTrade) != 2 {
+		return fmt.Errorf("ActiveAssetData: invalid availableToTrade %v", aux.AvailableToTrade)
+	}
+	data.MaxTradeSzs = [2]Decimal(aux.MaxTradeSzs)
+	data.AvailableToTrade = [2]Decimal(aux.AvailableToTrade)
+	return nil
+}
+
+// MaxTradeSz returns the maximum size that can be traded on the given side
+func (data *ActiveAssetData) MaxTradeSz(isBuy bool) Decimal {
+	if isBuy {
+		return data.MaxTradeSzs[0]
+	}
+	return data.MaxTradeSzs[1]
+}
+
 type MarginSummary struct {
-	AccountValue    float64 `json:"accountValue,string"`
-	TotalMarginUsed float64 `json:"totalMarginUsed,string"`
-	TotalNtlPos     float64 `json:"totalNtlPos,string"`
-	TotalRawUsd     float64 `json:"totalRawUsd,string"`
+	AccountValue    Decimal `json:"accountValue"`
+	TotalMarginUsed Decimal `json:"totalMarginUsed"`
+	TotalNtlPos     Decimal `json:"totalNtlPos"`
+	TotalRawUsd     Decimal `json:"totalRawUsd"`
 }
 
 type SpotMeta struct {
-	Universe []struct {
-		Tokens      []int  `json:"tokens"`
-		Name        string `json:"name"`
-		Index       int    `json:"index"`
-		IsCanonical bool   `json:"isCanonical"`
-	} `json:"universe"`
-	Tokens []struct {
-		Name        string `json:"name"`
-		SzDecimals  int    `json:"szDecimals"`
-		WeiDecimals int    `json:"weiDecimals"`
-		Index       int    `json:"index"`
-		TokenID     string `json:"tokenId"`
-		IsCanonical bool   `json:"isCanonical"`
-		EvmContract any    `json:"evmContract"`
-		FullName    any    `json:"fullName"`
-	} `json:"tokens"`
+	Universe []SpotPair  `json:"universe"`
+	Tokens   []SpotToken `json:"tokens"`
+}
+
+// Spot trading pair, Name is "@{index}" for all pairs but the canonical PURR/USDC
+type SpotPair struct {
+	Tokens      []int  `json:"tokens"`
+	Name        string `json:"name"`
+	Index       int    `json:"index"`
+	IsCanonical bool   `json:"isCanonical"`
+}
+
+type SpotToken struct {
+	Name        string `json:"name"`
+	SzDecimals  int    `json:"szDecimals"`
+	WeiDecimals int    `json:"weiDecimals"`
+	Index       int    `json:"index"`
+	TokenID     string `json:"tokenId"`
+	IsCanonical bool   `json:"isCanonical"`
+	EvmContract any    `json:"evmContract"`
+	FullName    any    `json:"fullName"`
+}
+
+// PairName returns the human readable name of a spot pair (e.g. "@107" -> "HYPE/USDC").
+// The second value is false if the pair or its tokens are unknown.
+func (meta *SpotMeta) PairName(coin string) (string, bool) {
+	for _, pair := range meta.Universe {
+		if pair.Name != coin {
+			continue
+		}
+		base, quote, ok := meta.pairTokens(pair)
+		if !ok {
+			return "", false
+		}
+		return base.Name + "/" + quote.Name, true
+	}
+	return "", false
+}
+
+// pairTokens returns the base and quote tokens of a spot pair
+func (meta *SpotMeta) pairTokens(pair SpotPair) (SpotToken, SpotToken, bool) {
+	if len(pair.Tokens) != 2 {
+		return SpotToken{}, SpotToken{}, false
+	}
+	var base, quote SpotToken
+	var baseFound, quoteFound bool
+	for _, token := range meta.Tokens {
+		if token.Index == pair.Tokens[0] {
+			base, baseFound = token, true
+		}
+		if token.Index == pair.Tokens[1] {
+			quote, quoteFound = token, true
+		}
+	}
+	return base, quote, baseFound && quoteFound
 }
 
 type Meta struct {
@@ -129,33 +288,65 @@ type Meta struct {
 
 type OrderFill struct {
 	Cloid         string       `json:"cloid"`
-	ClosedPnl     float64      `json:"closedPnl,string"`
+	ClosedPnl     Decimal      `json:"closedPnl"`
 	Coin          string       `json:"coin"`
 	Crossed       bool         `json:"crossed"`
 	Dir           string       `json:"dir"`
-	Fee           float64      `json:"fee,string"`
+	Fee           Decimal      `json:"fee"`
 	FeeToken      string       `json:"feeToken"`
 	Hash          string       `json:"hash"`
 	Oid           int          `json:"oid"`
-	Px            float64      `json:"px,string"`
+	Px            Decimal      `json:"px"`
 	Side          string       `json:"side"`
-	StartPosition string       `json:"startPosition"`
-	Sz            float64      `json:"sz,string"`
+	StartPosition Decimal      `json:"startPosition"`
+	Sz            Decimal      `json:"sz"`
 	Tid           int64        `json:"tid"`
 	Time          int64        `json:"time"`
 	Liquidation   *Liquidation `json:"liquidation"`
 }
 
+// Perpetual asset context (funding, open interest and prices)
+// MidPx, Premium and ImpactPxs are zero/empty when the book is too thin to compute them
 type Context struct {
-	DayNtlVlm    string   `json:"dayNtlVlm"`
-	Funding      string   `json:"funding"`
-	ImpactPxs    []string `json:"impactPxs"`
-	MarkPx       string   `json:"markPx"`
-	MidPx        string   `json:"midPx"`
-	OpenInterest string   `json:"openInterest"`
-	OraclePx     string   `json:"oraclePx"`
-	Premium      string   `json:"premium"`
-	PrevDayPx    string   `json:"prevDayPx"`
+	DayNtlVlm    Decimal   `json:"dayNtlVlm"`
+	DayBaseVlm   Decimal   `json:"dayBaseVlm"`
+	Funding      Decimal   `json:"funding"`
+	ImpactPxs    []Decimal `json:"impactPxs"`
+	MarkPx       Decimal   `json:"markPx"`
+	MidPx        Decimal   `json:"midPx"`
+	OpenInterest Decimal   `json:"openInterest"`
+	OraclePx     Decimal   `json:"oraclePx"`
+	Premium      Decimal   `json:"premium"`
+	PrevDayPx    Decimal   `json:"prevDayPx"`
+}
+
+// Response of metaAndAssetCtxs: perpetuals metadata and asset contexts in the same order
+type MetaAndAssetCtxs struct {
+	Meta     Meta
+	Contexts []Context
+}
+
+// UnmarshalJSON implements custom unmarshaling for MetaAndAssetCtxs.
+// The API returns a 2 elements array [meta, contexts].
+func (m *MetaAndAssetCtxs) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw) != 2 {
+		return fmt.Errorf("MetaAndAssetCtxs: expected 2 elements, got %d", len(raw))
+	}
+	if err := json.Unmarshal(raw[0], &m.Meta); err != nil {
+		return err
+	}
+	return json.Unmarshal(raw[1], &m.Contexts)
+}
+
+// Perpetual asset with its context
+type AssetContext struct {
+	AssetId int
+	Asset   Asset
+	Context Context
 }
 
 type HistoricalFundingRate struct {
@@ -165,21 +356,196 @@ type HistoricalFundingRate struct {
 	Time        int64  `json:"time"`
 }
 
+// AnnualizedRate returns the hourly Hyperliquid funding rate as an annualised rate
+func (rate HistoricalFundingRate) AnnualizedRate() (float64, error) {
+	parsed, err := strconv.ParseFloat(rate.FundingRate, 64)
+	if err != nil {
+		return 0, err
+	}
+	return AnnualizeFundingRate(parsed, HL_FUNDING_INTERVAL_HOURS), nil
+}
+
+// Venues reported by predictedFundings
+const (
+	VenueHlPerp    = "HlPerp"
+	VenueBinPerp   = "BinPerp"
+	VenueBybitPerp = "BybitPerp"
+)
+
+// Predicted funding of a coin on a venue
+// FundingRate is the rate for one funding interval of the venue
+type PredictedFunding struct {
+	Venue                string  `json:"venue"`
+	FundingRate          Decimal `json:"fundingRate"`
+	NextFundingTime      int64   `json:"nextFundingTime"`
+	FundingIntervalHours int     `json:"fundingIntervalHours,omitempty"`
+}
+
+// AnnualizedRate returns the predicted funding rate as an annualised rate.
+// If the API doesn't report the interval, Hyperliquid is assumed to be hourly and other venues 8-hourly.
+func (funding PredictedFunding) AnnualizedRate() float64 {
+	intervalHours := funding.FundingIntervalHours
+	if intervalHours == 0 {
+		intervalHours = CEX_FUNDING_INTERVAL_HOURS
+		if funding.Venue == VenueHlPerp {
+			intervalHours = HL_FUNDING_INTERVAL_HOURS
+		}
+	}
+	return AnnualizeFundingRate(funding.FundingRate.InexactFloat64(), intervalHours)
+}
+
+// Response of predictedFundings: predicted funding of every venue keyed by coin
+type PredictedFundings map[string][]PredictedFunding
+
+// UnmarshalJSON implements custom unmarshaling for PredictedFundings.
+// The API returns [[coin, [[venue, funding], ...]], ...], venues without a prediction are null and skipped.
+func (p *PredictedFundings) UnmarshalJSON(data []byte) error {
+	var coins [][2]json.RawMessage
+	if err := json.Unmarshal(data, &coins); err != nil {
+		return err
+	}
+	result := make(PredictedFundings, len(coins))
+	for _, coinFundings := range coins {
+		var coin string
+		if err := json.Unmarshal(coinFundings[0], &coin); err != nil {
+			return err
+		}
+		var venues [][2]json.RawMessage
+		if err := json.Unmarshal(coinFundings[1], &venues); err != nil {
+			return fmt.Errorf("PredictedFundings: coin %s: %w", coin, err)
+		}
+		fundings := make([]PredictedFunding, 0, len(venues))
+		for _, venueFunding := range venues {
+			var funding *PredictedFunding
+			if err := json.Unmarshal(venueFunding[1], &funding); err != nil {
+				return fmt.Errorf("PredictedFundings: coin %s: %w", coin, err)
+			}
+			if funding == nil {
+				continue
+			}
+			if err := json.Unmarshal(venueFunding[0], &funding.Venue); err != nil {
+				return err
+			}
+			fundings = append(fundings, *funding)
+		}
+		result[coin] = fundings
+	}
+	*p = result
+	return nil
+}
+
+// Venue returns the predicted funding of a coin on the given venue
+func (p PredictedFundings) Venue(coin string, venue string) (PredictedFunding, bool) {
+	for _, funding := range p[coin] {
+		if funding.Venue == venue {
+			return funding, true
+		}
+	}
+	return PredictedFunding{}, false
+}
+
+type L2BookRequest struct {
+	Typez    string `json:"type"`
+	Coin     string `json:"coin"`
+	NSigFigs *int   `json:"nSigFigs,omitempty"`
+	Mantissa *int   `json:"mantissa,omitempty"`
+}
+
+// Aggregated price level of the book, N is the number of orders at this level
+type BookLevel struct {
+	Px Decimal `json:"px"`
+	Sz Decimal `json:"sz"`
+	N  int     `json:"n"`
+}
+
+// Bids are sorted from the best (highest) price, Asks from the best (lowest) price.
+// Levels holds the raw [bids, asks] pair returned by the API.
 type L2BookSnapshot struct {
-	Coin   string `json:"coin"`
-	Time   int64  `json:"time"`
-	Levels [][]struct {
-		Px float64 `json:"px,string"`
-		Sz float64 `json:"sz,string"`
-		N  int     `json:"n"`
-	} `json:"levels"`
+	Coin   string        `json:"coin"`
+	Time   int64         `json:"time"`
+	Levels [][]BookLevel `json:"levels"`
+	Bids   []BookLevel   `json:"-"`
+	Asks   []BookLevel   `json:"-"`
+}
+
+// UnmarshalJSON implements custom unmarshaling for L2BookSnapshot.
+// It splits Levels into Bids and Asks.
+func (book *L2BookSnapshot) UnmarshalJSON(data []byte) error {
+	type Alias L2BookSnapshot
+	var alias Alias
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return err
+	}
+	*book = L2BookSnapshot(alias)
+	if len(book.Levels) != 2 {
+		return fmt.Errorf("L2BookSnapshot: expected 2 sides, got %d", len(book.Levels))
+	}
+	book.Bids = book.Levels[0]
+	book.Asks = book.Levels[1]
+	return nil
+}
+
+// Candle interval supported by candleSnapshot
+type CandleInterval string
+
+const (
+	Candle1m  CandleInterval = "1m"
+	Candle3m  CandleInterval = "3m"
+	Candle5m  CandleInterval = "5m"
+	Candle15m CandleInterval = "15m"
+	Candle30m CandleInterval = "30m"
+	Candle1h  CandleInterval = "1h"
+	Candle2h  CandleInterval = "2h"
+	Candle4h  CandleInterval = "4h"
+	Candle8h  CandleInterval = "8h"
+	Candle12h CandleInterval = "12h"
+	Candle1d  CandleInterval = "1d"
+	Candle3d  CandleInterval = "3d"
+	Candle1w  CandleInterval = "1w"
+	Candle1M  CandleInterval = "1M"
+)
+
+var candleIntervalDurations = map[CandleInterval]time.Duration{
+	Candle1m:  time.Minute,
+	Candle3m:  3 * time.Minute,
+	Candle5m:  5 * time.Minute,
+	Candle15m: 15 * time.Minute,
+	Candle30m: 30 * time.Minute,
+	Candle1h:  time.Hour,
+	Candle2h:  2 * time.Hour,
+	Candle4h:  4 * time.Hour,
+	Candle8h:  8 * time.Hour,
+	Candle12h: 12 * time.Hour,
+	Candle1d:  24 * time.Hour,
+	Candle3d:  3 * 24 * time.Hour,
+	Candle1w:  7 * 24 * time.Hour,
+	Candle1M:  30 * 24 * time.Hour,
+}
+
+// IsValid returns true if the interval is supported by the API
+func (interval CandleInterval) IsValid() bool {
+	_, ok := candleIntervalDurations[interval]
+	return ok
+}
+
+// Duration returns the length of a candle, 1M is approximated as 30 days
+func (interval CandleInterval) Duration() time.Duration {
+	return candleIntervalDurations[interval]
+}
+
+// NextOpenTime returns the open time of the candle following the one opened at openTime (ms)
+func (interval CandleInterval) NextOpenTime(openTime int64) int64 {
+	if interval == Candle1M {
+		return time.UnixMilli(openTime).UTC().AddDate(0, 1, 0).UnixMilli()
+	}
+	return openTime + interval.Duration().Milliseconds()
 }
 
 type CandleSnapshotSubRequest struct {
-	Coin      string `json:"coin"`
-	Interval  string `json:"interval"`
-	StartTime int64  `json:"startTime"`
-	EndTime   int64  `json:"endTime"`
+	Coin      string         `json:"coin"`
+	Interval  CandleInterval `json:"interval"`
+	StartTime int64          `json:"startTime"`
+	EndTime   int64          `json:"endTime"`
 }
 
 type CandleSnapshotRequest struct {
@@ -188,16 +554,34 @@ type CandleSnapshotRequest struct {
 }
 
 type CandleSnapshot struct {
-	CloseTime int64   `json:"t"`
-	OpenTime  int64   `json:"T"`
-	Symbol    string  `json:"s"`
-	Interval  string  `json:"i"`
-	Open      float64 `json:"o,string"`
-	Close     float64 `json:"c,string"`
-	High      float64 `json:"h,string"`
-	Low       float64 `json:"l,string"`
-	Volume    float64 `json:"v,string"`
-	N         int     `json:"n"`
+	OpenTime  int64          `json:"t"`
+	CloseTime int64          `json:"T"`
+	Symbol    string         `json:"s"`
+	Interval  CandleInterval `json:"i"`
+	Open      Decimal        `json:"o"`
+	Close     Decimal        `json:"c"`
+	High      Decimal        `json:"h"`
+	Low       Decimal        `json:"l"`
+	Volume    Decimal        `json:"v"`
+	N         int            `json:"n"`
+}
+
+// Missing candles between two consecutive candles, From and To are their open times
+type CandleGap struct {
+	From int64
+	To   int64
+}
+
+// CandleGaps returns the gaps of a list of candles sorted by open time
+// Note that the API doesn't return candles for periods without trades.
+func CandleGaps(candles []CandleSnapshot, interval CandleInterval) []CandleGap {
+	var gaps []CandleGap
+	for i := 1; i < len(candles); i++ {
+		if interval.NextOpenTime(candles[i-1].OpenTime) < candles[i].OpenTime {
+			gaps = append(gaps, CandleGap{From: candles[i-1].OpenTime, To: candles[i].OpenTime})
+		}
+	}
+	return gaps
 }
 
 type NonFundingUpdate struct {
@@ -206,6 +590,242 @@ type NonFundingUpdate struct {
 	Delta NonFundingDelta `json:"delta"`
 }
 
+// UnmarshalJSON implements custom unmarshaling for NonFundingUpdate.
+// The delta is decoded into the concrete type matching its "type" field.
+func (update *NonFundingUpdate) UnmarshalJSON(data []byte) error {
+	type Alias NonFundingUpdate
+	aux := struct {
+		*Alias
+		Delta json.RawMessage `json:"delta"`
+	}{Alias: (*Alias)(update)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	delta, err := decodeNonFundingDelta(aux.Delta)
+	if err != nil {
+		return fmt.Errorf("NonFundingUpdate %s: %w", update.Hash, err)
+	}
+	update.Delta = delta
+	return nil
+}
+
+// NonFundingDelta is a non-funding ledger update.
+// Use a type switch to access the fields of a concrete delta:
+//
+//	switch delta := update.Delta.(type) {
+//	case *DepositDelta:
+//		...
+//	case *WithdrawDelta:
+//		...
+//	}
+//
+// Deltas of types unknown to this package are returned as *UnknownDelta.
+type NonFundingDelta interface {
+	DeltaType() string
+}
+
+func decodeNonFundingDelta(data []byte) (NonFundingDelta, error) {
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+	var delta NonFundingDelta
+	switch header.Type {
+	case "deposit":
+		delta = &DepositDelta{}
+	case "withdraw":
+		delta = &WithdrawDelta{}
+	case "internalTransfer":
+		delta = &InternalTransferDelta{}
+	case "subAccountTransfer":
+		delta = &SubAccountTransferDelta{}
+	case "spotTransfer":
+		delta = &SpotTransferDelta{}
+	case "send":
+		delta = &SendDelta{}
+	case "accountClassTransfer":
+		delta = &AccountClassTransferDelta{}
+	case "liquidation":
+		delta = &LiquidationDelta{}
+	case "vaultCreate":
+		delta = &VaultCreateDelta{}
+	case "vaultDeposit":
+		delta = &VaultDepositDelta{}
+	case "vaultWithdraw":
+		delta = &VaultWithdrawDelta{}
+	case "vaultDistribution":
+		delta = &VaultDistributionDelta{}
+	case "spotGenesis":
+		delta = &SpotGenesisDelta{}
+	case "rewardsClaim":
+		delta = &RewardsClaimDelta{}
+	case "cStakingTransfer":
+		delta = &CStakingTransferDelta{}
+	default:
+		return &UnknownDelta{Type: header.Type, Raw: json.RawMessage(data)}, nil
+	}
+	if err := json.Unmarshal(data, delta); err != nil {
+		return nil, fmt.Errorf("invalid %s delta: %w", header.Type, err)
+	}
+	return delta, nil
+}
+
+// USDC deposit from Arbitrum
+type DepositDelta struct {
+	Type string  `json:"type"`
+	Usdc Decimal `json:"usdc"`
+}
+
+// USDC withdrawal to Arbitrum
+type WithdrawDelta struct {
+	Type  string  `json:"type"`
+	Usdc  Decimal `json:"usdc"`
+	Nonce int64   `json:"nonce"`
+	Fee   Decimal `json:"fee"`
+}
+
+// USDC transfer between perp accounts (usdSend)
+type InternalTransferDelta struct {
+	Type        string  `json:"type"`
+	Usdc        Decimal `json:"usdc"`
+	User        string  `json:"user"`
+	Destination string  `json:"destination"`
+	Fee         Decimal `json:"fee"`
+}
+
+// USDC transfer between a master account and its sub-account
+type SubAccountTransferDelta struct {
+	Type        string  `json:"type"`
+	Usdc        Decimal `json:"usdc"`
+	User        string  `json:"user"`
+	Destination string  `json:"destination"`
+}
+
+// Spot token transfer between accounts (spotSend)
+type SpotTransferDelta struct {
+	Type           string  `json:"type"`
+	Token          string  `json:"token"`
+	Amount         Decimal `json:"amount"`
+	UsdcValue      Decimal `json:"usdcValue"`
+	User           string  `json:"user"`
+	Destination    string  `json:"destination"`
+	Fee            Decimal `json:"fee"`
+	NativeTokenFee Decimal `json:"nativeTokenFee"`
+	Nonce          int64   `json:"nonce"`
+}
+
+// Token transfer between accounts and/or dexs (sendAsset)
+type SendDelta struct {
+	Type           string  `json:"type"`
+	User           string  `json:"user"`
+	Destination    string  `json:"destination"`
+	SourceDex      string  `json:"sourceDex"`
+	DestinationDex string  `json:"destinationDex"`
+	Token          string  `json:"token"`
+	Amount         Decimal `json:"amount"`
+	UsdcValue      Decimal `json:"usdcValue"`
+	Fee            Decimal `json:"fee"`
+	NativeTokenFee Decimal `json:"nativeTokenFee"`
+	Nonce          int64   `json:"nonce"`
+}
+
+// USDC transfer between the spot and perp balances of the same account
+type AccountClassTransferDelta struct {
+	Type   string  `json:"type"`
+	Usdc   Decimal `json:"usdc"`
+	ToPerp bool    `json:"toPerp"`
+}
+
+type LiquidatedPosition struct {
+	Coin string  `json:"coin"`
+	Szi  Decimal `json:"szi"`
+}
+
+type LiquidationDelta struct {
+	Type                string               `json:"type"`
+	LiquidatedNtlPos    Decimal              `json:"liquidatedNtlPos"`
+	AccountValue        Decimal              `json:"accountValue"`
+	LeverageType        string               `json:"leverageType"`
+	LiquidatedPositions []LiquidatedPosition `json:"liquidatedPositions"`
+}
+
+type VaultCreateDelta struct {
+	Type  string  `json:"type"`
+	Vault string  `json:"vault"`
+	Usdc  Decimal `json:"usdc"`
+	Fee   Decimal `json:"fee"`
+}
+
+type VaultDepositDelta struct {
+	Type  string  `json:"type"`
+	Vault string  `json:"vault"`
+	Usdc  Decimal `json:"usdc"`
+}
+
+type VaultWithdrawDelta struct {
+	Type            string  `json:"type"`
+	Vault           string  `json:"vault"`
+	User            string  `json:"user"`
+	RequestedUsd    Decimal `json:"requestedUsd"`
+	Commission      Decimal `json:"commission"`
+	ClosingCost     Decimal `json:"closingCost"`
+	Basis           Decimal `json:"basis"`
+	NetWithdrawnUsd Decimal `json:"netWithdrawnUsd"`
+}
+
+// Vault leader commission or profit distribution
+type VaultDistributionDelta struct {
+	Type  string  `json:"type"`
+	Vault string  `json:"vault"`
+	Usdc  Decimal `json:"usdc"`
+}
+
+// Spot tokens received at genesis (airdrops)
+type SpotGenesisDelta struct {
+	Type   string  `json:"type"`
+	Token  string  `json:"token"`
+	Amount Decimal `json:"amount"`
+}
+
+// Referral or builder rewards claimed to the spot balance
+type RewardsClaimDelta struct {
+	Type   string  `json:"type"`
+	Amount Decimal `json:"amount"`
+}
+
+// Transfer between the spot balance and the staking balance
+type CStakingTransferDelta struct {
+	Type      string  `json:"type"`
+	Token     string  `json:"token"`
+	Amount    Decimal `json:"amount"`
+	IsDeposit bool    `json:"isDeposit"`
+}
+
+// Delta of a type unknown to this package, Raw holds the original JSON
+type UnknownDelta struct {
+	Type string
+	Raw  json.RawMessage
+}
+
+func (d *DepositDelta) DeltaType() string              { return d.Type }
+func (d *WithdrawDelta) DeltaType() string             { return d.Type }
+func (d *InternalTransferDelta) DeltaType() string     { return d.Type }
+func (d *SubAccountTransferDelta) DeltaType() string   { return d.Type }
+func (d *SpotTransferDelta) DeltaType() string         { return d.Type }
+func (d *SendDelta) DeltaType() string                 { return d.Type }
+func (d *AccountClassTransferDelta) DeltaType() string { return d.Type }
+func (d *LiquidationDelta) DeltaType() string          { return d.Type }
+func (d *VaultCreateDelta) DeltaType() string          { return d.Type }
+func (d *VaultDepositDelta) DeltaType() string         { return d.Type }
+func (d *VaultWithdrawDelta) DeltaType() string        { return d.Type }
+func (d *VaultDistributionDelta) DeltaType() string    { return d.Type }
+func (d *SpotGenesisDelta) DeltaType() string          { return d.Type }
+func (d *RewardsClaimDelta) DeltaType() string         { return d.Type }
+func (d *CStakingTransferDelta) DeltaType() string     { return d.Type }
+func (d *UnknownDelta) DeltaType() string              { return d.Type }
+
 type FundingUpdate struct {
 	Hash  string       `json:"hash"`
 	Time  int64        `json:"time"`
@@ -213,13 +833,30 @@ type FundingUpdate struct {
 }
 
 type RatesLimits struct {
-	CumVlm        float64 `json:"cumVlm,string"`
+	CumVlm        Decimal `json:"cumVlm"`
 	NRequestsUsed int     `json:"nRequestsUsed"`
 	NRequestsCap  int     `json:"nRequestsCap"`
 }
 
-type SpotMetaAndAssetCtxsResponse [2]interface{} // Array of exactly 2 elements
+// Spot asset context (prices, volumes and supply)
+type SpotContext struct {
+	Coin              string  `json:"coin"`
+	DayNtlVlm         Decimal `json:"dayNtlVlm"`
+	DayBaseVlm        Decimal `json:"dayBaseVlm"`
+	MarkPx            Decimal `json:"markPx"`
+	MidPx             Decimal `json:"midPx"`
+	PrevDayPx         Decimal `json:"prevDayPx"`
+	CirculatingSupply Decimal `json:"circulatingSupply"`
+	TotalSupply       Decimal `json:"totalSupply"`
+}
+
+// Raw response of spotMetaAndAssetCtxs, an array of exactly 2 elements
+//
+// Deprecated: use SpotMetaAndAssetCtxs or GetSpotMetaAndAssetCtxs
+type SpotMetaAndAssetCtxsResponse [2]interface{}
 
+// Spot asset context with the values as returned by the exchange, MidPx is empty if the pair has no mid price
+// Use SpotContext (see GetSpotMetaAndAssetCtxs) for parsed values.
 type Market struct {
 	PrevDayPx         string `json:"prevDayPx,omitempty"`
 	DayNtlVlm         string `json:"dayNtlVlm,omitempty"`
@@ -230,3 +867,251 @@ type Market struct {
 	TotalSupply       string `json:"totalSupply,omitempty"`
 	DayBaseVlm        string `json:"dayBaseVlm,omitempty"`
 }
+
+// Response of spotMetaAndAssetCtxs: spot metadata and contexts of its pairs
+type SpotMetaAndAssetCtxs struct {
+	Meta     SpotMeta
+	Contexts []SpotContext
+}
+
+// UnmarshalJSON implements custom unmarshaling for SpotMetaAndAssetCtxs.
+// The API returns a 2 elements array [meta, contexts].
+func (m *SpotMetaAndAssetCtxs) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw) != 2 {
+		return fmt.Errorf("SpotMetaAndAssetCtxs: expected 2 elements, got %d", len(raw))
+	}
+	if err := json.Unmarshal(raw[0], &m.Meta); err != nil {
+		return err
+	}
+	return json.Unmarshal(raw[1], &m.Contexts)
+}
+
+// Spot pair joined with its tokens and context
+// Coin is the name used by the API (e.g. "@107"), Name is the human readable one (e.g. "HYPE/USDC")
+type SpotAssetContext struct {
+	Coin    string
+	Name    string
+	Pair    SpotPair
+	Base    SpotToken
+	Quote   SpotToken
+	Context SpotContext
+}
+
+// Response of userFees: volume history, current rates and the fee schedule
+// Rates are fractions of the notional (0.00045 = 0.045%), negative maker rates are rebates
+type UserFees struct {
+	DailyUserVlm                []DailyUserVolume `json:"dailyUserVlm"`
+	FeeSchedule                 FeeSchedule       `json:"feeSchedule"`
+	UserCrossRate               Decimal           `json:"userCrossRate"`
+	UserAddRate                 Decimal           `json:"userAddRate"`
+	UserSpotCrossRate           Decimal           `json:"userSpotCrossRate"`
+	UserSpotAddRate             Decimal           `json:"userSpotAddRate"`
+	ActiveReferralDiscount      Decimal           `json:"activeReferralDiscount"`
+	ActiveStakingDiscount       *StakingDiscount  `json:"activeStakingDiscount"`
+	FeeTrialReward              Decimal           `json:"feeTrialReward"`
+	NextTrialAvailableTimestamp *int64            `json:"nextTrialAvailableTimestamp"`
+}
+
+type DailyUserVolume struct {
+	Date      string  `json:"date"`
+	UserCross Decimal `json:"userCross"`
+	UserAdd   Decimal `json:"userAdd"`
+	Exchange  Decimal `json:"exchange"`
+}
+
+type FeeSchedule struct {
+	Cross                Decimal           `json:"cross"`
+	Add                  Decimal           `json:"add"`
+	SpotCross            Decimal           `json:"spotCross"`
+	SpotAdd              Decimal           `json:"spotAdd"`
+	Tiers                FeeTiers          `json:"tiers"`
+	ReferralDiscount     Decimal           `json:"referralDiscount"`
+	StakingDiscountTiers []StakingDiscount `json:"stakingDiscountTiers"`
+}
+
+type FeeTiers struct {
+	Vip []VipFeeTier `json:"vip"`
+	Mm  []MmFeeTier  `json:"mm"`
+}
+
+// Volume based tier, applies when the 14 day volume is above NtlCutoff
+type VipFeeTier struct {
+	NtlCutoff Decimal `json:"ntlCutoff"`
+	Cross     Decimal `json:"cross"`
+	Add       Decimal `json:"add"`
+	SpotCross Decimal `json:"spotCross"`
+	SpotAdd   Decimal `json:"spotAdd"`
+}
+
+// Market maker rebate tier, applies when the maker volume share is above MakerFractionCutoff
+type MmFeeTier struct {
+	MakerFractionCutoff Decimal `json:"makerFractionCutoff"`
+	Add                 Decimal `json:"add"`
+}
+
+type StakingDiscount struct {
+	BpsOfMaxSupply Decimal `json:"bpsOfMaxSupply"`
+	Discount       Decimal `json:"discount"`
+}
+
+// TakerRate returns the account's current taker (cross) rate
+func (fees *UserFees) TakerRate(isSpot bool) Decimal {
+	if isSpot {
+		return fees.UserSpotCrossRate
+	}
+	return fees.UserCrossRate
+}
+
+// MakerRate returns the account's current maker (add) rate
+func (fees *UserFees) MakerRate(isSpot bool) Decimal {
+	if isSpot {
+		return fees.UserSpotAddRate
+	}
+	return fees.UserAddRate
+}
+
+// EstimateFee estimates the fee in quote currency for filling the whole order at its limit price.
+// Alo orders always add liquidity and are charged the maker rate, all other orders are
+// estimated at the taker rate as the worst case. A negative result is a rebate.
+func (fees *UserFees) EstimateFee(request OrderRequest, isSpot bool) Decimal {
+	rate := fees.TakerRate(isSpot)
+	if request.OrderType.Limit != nil && request.OrderType.Limit.Tif == TifAlo {
+		rate = fees.MakerRate(isSpot)
+	}
+	return request.Sz.Mul(request.LimitPx).Mul(rate)
+}
+
+// Single point of a time series, encoded by the API as [time, "value"]
+// Value is a float64, unlike the prices and amounts of the other types: the series are
+// chart data meant for the statistics of stats.go, not for exact accounting.
+type TimeValue struct {
+	Time  int64
+	Value float64
+}
+
+// UnmarshalJSON implements custom unmarshaling for TimeValue.
+func (tv *TimeValue) UnmarshalJSON(data []byte) error {
+	var raw [2]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("TimeValue: expected [time, value] pair: %w", err)
+	}
+	if err := json.Unmarshal(raw[0], &tv.Time); err != nil {
+		return err
+	}
+	var value string
+	if err := json.Unmarshal(raw[1], &value); err != nil {
+		return err
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("TimeValue: invalid value %q: %w", value, err)
+	}
+	tv.Value = parsed
+	return nil
+}
+
+type TimeSeries []TimeValue
+
+// Account value and cumulative PnL history of a portfolio window
+// Like the values of TimeSeries, Vlm stays a float64.
+type PortfolioHistory struct {
+	AccountValueHistory TimeSeries `json:"accountValueHistory"`
+	PnlHistory          TimeSeries `json:"pnlHistory"`
+	Vlm                 float64    `json:"vlm,string"`
+}
+
+// Response of portfolio: history windows for the total account and for perps only
+type Portfolio struct {
+	Day         PortfolioHistory
+	Week        PortfolioHistory
+	Month       PortfolioHistory
+	AllTime     PortfolioHistory
+	PerpDay     PortfolioHistory
+	PerpWeek    PortfolioHistory
+	PerpMonth   PortfolioHistory
+	PerpAllTime PortfolioHistory
+}
+
+// UnmarshalJSON implements custom unmarshaling for Portfolio.
+// The API returns an array of [window, history] pairs, unknown windows are ignored.
+func (p *Portfolio) UnmarshalJSON(data []byte) error {
+	var windows [][2]json.RawMessage
+	if err := json.Unmarshal(data, &windows); err != nil {
+		return err
+	}
+	for _, window := range windows {
+		var name string
+		if err := json.Unmarshal(window[0], &name); err != nil {
+			return err
+		}
+		var target *PortfolioHistory
+		switch name {
+		case "day":
+			target = &p.Day
+		case "week":
+			target = &p.Week
+		case "month":
+			target = &p.Month
+		case "allTime":
+			target = &p.AllTime
+		case "perpDay":
+			target = &p.PerpDay
+		case "perpWeek":
+			target = &p.PerpWeek
+		case "perpMonth":
+			target = &p.PerpMonth
+		case "perpAllTime":
+			target = &p.PerpAllTime
+		default:
+			continue
+		}
+		if err := json.Unmarshal(window[1], target); err != nil {
+			return fmt.Errorf("Portfolio: window %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Signers of a multi-sig account: the actions must be signed by at least Threshold of the AuthorizedUsers
+type MultiSigSigners struct {
+	AuthorizedUsers []string `json:"authorizedUsers"`
+	Threshold       int      `json:"threshold"`
+}
+
+// Message pushed by the websocket API, e.g. {"channel": "orderUpdates", "data": [...]}
+type WsMessage struct {
+	Channel string          `json:"channel"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// Data of the userFills websocket channel. The first message is a snapshot of the recent fills.
+type WsUserFills struct {
+	IsSnapshot bool        `json:"isSnapshot"`
+	User       string      `json:"user"`
+	Fills      []OrderFill `json:"fills"`
+}
+
+// Funding payment of the userFundings websocket channel, Usdc is negative if the funding was paid
+type WsFunding struct {
+	Time        int64   `json:"time"`
+	Coin        string  `json:"coin"`
+	Usdc        Decimal `json:"usdc"`
+	Szi         Decimal `json:"szi"`
+	FundingRate Decimal `json:"fundingRate"`
+}
+
+// Data of the userFundings websocket channel. The first message is a snapshot of the recent fundings.
+type WsUserFundings struct {
+	IsSnapshot bool        `json:"isSnapshot"`
+	User       string      `json:"user"`
+	Fundings   []WsFunding `json:"fundings"`
+}
+
+// Data of the allMids websocket channel
+type WsAllMids struct {
+	Mids map[string]string `json:"mids"`
+}
@@ -0,0 +1,242 @@
+package hyperliquid
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// MultiSigCollector gathers the signatures of the authorized users of a multi-sig account for one action
+// and sends the action signed by the outer signer, the signer of the client.
+//
+// Each authorized user signs a copy of Envelope(), e.g. on an offline host with SigningEnvelope.Sign,
+// and the signed envelopes are added with Add. The outer signer must be an authorized user
+// and adds its own signature with SignOuter or Add.
+//
+//	collector, _ := api.NewMultiSigCollector(treasury, action, time.Hour)
+//	envelope := collector.Envelope() // Sent to each authorized user
+//	collector.Add(signedEnvelope)
+//	res, err := SubmitMultiSig[DefaultExchangeResponse](api, collector)
+//
+// https://hyperliquid.gitbook.io/hyperliquid-docs/hypercore/multi-sig
+type MultiSigCollector struct {
+	multiSigUser     string
+	outerSigner      string
+	signatureChainId string
+	action           json.RawMessage // Inner action sent in the payload
+	envelope         SigningEnvelope // Unsigned envelope of the authorized users
+	signatures       map[common.Address]RsvSignature
+}
+
+// NewMultiSigCollector prepares the action of the multi-sig user for the signatures of its authorized users.
+// The nonce of the action is the next nonce of the outer signer. The envelopes expire after ttl.
+func (api *ExchangeAPI) NewMultiSigCollector(multiSigUser string, action Action, ttl time.Duration) (*MultiSigCollector, error) {
+	if api.Signer() == nil {
+		return nil, APIError{Message: "Signer not set, the outer signer of a multi-sig action must be an authorized user"}
+	}
+	if !common.IsHexAddress(multiSigUser) {
+		return nil, APIError{Message: fmt.Sprintf("Invalid multi-sig user: %s", multiSigUser)}
+	}
+	nonce, err := api.nextNonce()
+	if err != nil {
+		return nil, err
+	}
+	multiSigUser = strings.ToLower(multiSigUser)
+	outerSigner := strings.ToLower(api.Signer().Address().Hex())
+	signatureChainId, chain := api.getChainParams()
+	var vaultAddress *string
+	var payload []byte
+	if userSigned, ok := action.(UserSignedAction); ok {
+		action = userSigned.WithSignatureParams(nonce, signatureChainId, chain)
+	} else {
+		vaultAddress = api.getVaultAddress()
+	}
+	inner, err := json.Marshal(action)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := action.(UserSignedAction); ok {
+		// The user signed action with the multi-sig user and the outer signer
+		payload, err = appendJSONFields(inner, orderedMap{{"payloadMultiSigUser", multiSigUser}, {"outerSigner", outerSigner}})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// The L1 action is signed as [multiSigUser, outerSigner, action]
+		payload, err = json.Marshal([]any{multiSigUser, outerSigner, json.RawMessage(inner)})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &MultiSigCollector{
+		multiSigUser:     multiSigUser,
+		outerSigner:      outerSigner,
+		signatureChainId: signatureChainId,
+		action:           inner,
+		envelope: SigningEnvelope{
+			Action:       payload,
+			Nonce:        nonce,
+			VaultAddress: vaultAddress,
+			Network:      getNetworkName(api.IsMainnet()),
+			ExpiresAt:    api.clock().Add(ttl).UnixMilli(),
+		},
+		signatures: make(map[common.Address]RsvSignature),
+	}, nil
+}
+
+// Envelope returns a copy of the unsigned envelope to be signed by an authorized user.
+func (collector *MultiSigCollector) Envelope() *SigningEnvelope {
+	envelope := collector.envelope
+	return &envelope
+}
+
+// Nonce returns the nonce of the multi-sig action.
+func (collector *MultiSigCollector) Nonce() uint64 {
+	return collector.envelope.Nonce
+}
+
+// Add verifies the envelope signed by an authorized user and adds its signature.
+// The envelope must be a copy of Envelope().
+func (collector *MultiSigCollector) Add(signed *SigningEnvelope) error {
+	expected := collector.envelope
+	if signed.Nonce != expected.Nonce || signed.Network != expected.Network ||
+		!bytes.Equal(signed.Action, expected.Action) || !sameVaultAddress(signed.VaultAddress, expected.VaultAddress) {
+		return APIError{Message: "Signed envelope is not the envelope of the multi-sig action"}
+	}
+	if signed.Signature == nil {
+		return APIError{Message: "Signing envelope is not signed"}
+	}
+	request := signed.Request()
+	signer, err := RecoverSigner(request, signed.IsMainnet())
+	if err != nil {
+		return err
+	}
+	if signed.Signer != "" && common.HexToAddress(signed.Signer) != signer {
+		return fmt.Errorf("%w: %s, expected %s", ErrSignerMismatch, signer, signed.Signer)
+	}
+	collector.signatures[signer] = *signed.Signature
+	return nil
+}
+
+// SignOuter adds the signature of the outer signer as an authorized user.
+func (collector *MultiSigCollector) SignOuter(api *ExchangeAPI) error {
+	envelope := collector.Envelope()
+	if err := envelope.Sign(api.Signer(), api.clock()); err != nil {
+		return err
+	}
+	return collector.Add(envelope)
+}
+
+// Signers returns the addresses that signed, sorted.
+func (collector *MultiSigCollector) Signers() []common.Address {
+	signers := make([]common.Address, 0, len(collector.signatures))
+	for signer := range collector.signatures {
+		signers = append(signers, signer)
+	}
+	slices.SortFunc(signers, func(a, b common.Address) int { return bytes.Compare(a.Bytes(), b.Bytes()) })
+	return signers
+}
+
+// Check returns an error if a signer is not an authorized user or if there are fewer signatures than the threshold.
+// The signers of the account are returned by InfoAPI.GetMultiSigSigners.
+func (collector *MultiSigCollector) Check(signers *MultiSigSigners) error {
+	if signers == nil {
+		return APIError{Message: fmt.Sprintf("%s is not a multi-sig user", collector.multiSigUser)}
+	}
+	for _, signer := range collector.Signers() {
+		authorized := slices.ContainsFunc(signers.AuthorizedUsers, func(user string) bool {
+			return common.HexToAddress(user) == signer
+		})
+		if !authorized {
+			return APIError{Message: fmt.Sprintf("%s is not an authorized user of %s", signer, collector.multiSigUser)}
+		}
+	}
+	if len(collector.signatures) < signers.Threshold {
+		return APIError{Message: fmt.Sprintf("%d signatures, the threshold of %s is %d", len(collector.signatures), collector.multiSigUser, signers.Threshold)}
+	}
+	return nil
+}
+
+// Action returns the multiSig action with the signatures sorted by signer address.
+func (collector *MultiSigCollector) Action() MultiSigAction {
+	signatures := make([]RsvSignature, 0, len(collector.signatures))
+	for _, signer := range collector.Signers() {
+		signatures = append(signatures, collector.signatures[signer])
+	}
+	return MultiSigAction{
+		Type:             "multiSig",
+		SignatureChainID: collector.signatureChainId,
+		Signatures:       signatures,
+		Payload: MultiSigPayload{
+			MultiSigUser: collector.multiSigUser,
+			OuterSigner:  collector.outerSigner,
+			Action:       collector.action,
+		},
+	}
+}
+
+// SubmitMultiSig signs the multiSig action of the collector with the outer signer and sends it.
+// R is the response of the inner action, e.g. OrderResponse for orders.
+func SubmitMultiSig[R any](api *ExchangeAPI, collector *MultiSigCollector) (*R, error) {
+	if api.Signer() == nil || strings.ToLower(api.Signer().Address().Hex()) != collector.outerSigner {
+		return nil, APIError{Message: fmt.Sprintf("The multi-sig action must be sent by the outer signer %s", collector.outerSigner)}
+	}
+	if len(collector.signatures) == 0 {
+		return nil, APIError{Message: "No signatures collected"}
+	}
+	if collector.envelope.Network != getNetworkName(api.IsMainnet()) {
+		return nil, fmt.Errorf("%w: multi-sig action is for %s", ErrNetworkMismatch, collector.envelope.Network)
+	}
+	if api.clock().UnixMilli() > collector.envelope.ExpiresAt {
+		return nil, ErrEnvelopeExpired
+	}
+	action := collector.Action()
+	vaultAddress := ""
+	if collector.envelope.VaultAddress != nil {
+		vaultAddress = *collector.envelope.VaultAddress
+	}
+	hash, err := multiSigActionHash(action, vaultAddress, collector.Nonce())
+	if err != nil {
+		return nil, err
+	}
+	signRequest := buildMultiSigSignRequest(hash, action.SignatureChainID, collector.Nonce(), api.IsMainnet())
+	v, r, s, err := api.Sign(signRequest)
+	if err != nil {
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:       action,
+		Nonce:        collector.Nonce(),
+		Signature:    ToTypedSig(r, s, v),
+		VaultAddress: collector.envelope.VaultAddress,
+	}
+	return MakeUniversalRequest[R](api, request)
+}
+
+// appendJSONFields returns the JSON object with the fields added at the end
+func appendJSONFields(object json.RawMessage, fields orderedMap) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(bytes.TrimSuffix(bytes.TrimSpace(object), []byte("}")))
+	for _, field := range fields {
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		key, _ := json.Marshal(field.key)
+		fmt.Fprintf(&buf, ",%s:%s", key, value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func sameVaultAddress(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return strings.EqualFold(*a, *b)
+}
@@ -0,0 +1,148 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMultiSigCollector(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+	treasury := "0x00000000000000000000000000000000000000AA"
+	other, _ := NewPKeyManager("1111111111111111111111111111111111111111111111111111111111111111")
+	outer, _ := NewPKeyManager(testPrivateKey)
+	api, actionType := getVerifyingExchangeAPI(t, outer.Address())
+	api.SetClock(func() time.Time { return now })
+	signers := &MultiSigSigners{
+		AuthorizedUsers: []string{strings.ToLower(api.Signer().Address().Hex()), strings.ToLower(other.Address().Hex())},
+		Threshold:       2,
+	}
+
+	actions := []Action{
+		UpdateLeverageAction{Type: "updateLeverage", Asset: 1, IsCross: true, Leverage: 5},
+		usdSendAction("0x0000000000000000000000000000000000000002", 100),
+	}
+	for _, action := range actions {
+		t.Run(action.ActionType(), func(t *testing.T) {
+			collector, err := api.NewMultiSigCollector(treasury, action, time.Hour)
+			if err != nil {
+				t.Fatalf("NewMultiSigCollector() error = %v", err)
+			}
+			if err := collector.SignOuter(api); err != nil {
+				t.Fatalf("SignOuter() error = %v", err)
+			}
+			if err := collector.Check(signers); err == nil {
+				t.Errorf("Check() with 1 signature error = nil, want error")
+			}
+
+			// The other authorized user signs offline
+			data, _ := json.Marshal(collector.Envelope())
+			envelope, err := ParseSigningEnvelope(data)
+			if err != nil {
+				t.Fatalf("ParseSigningEnvelope() error = %v", err)
+			}
+			if envelope.ActionType() != action.ActionType() {
+				t.Errorf("ActionType() = %s, want %s", envelope.ActionType(), action.ActionType())
+			}
+			if err := envelope.Sign(other, now); err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+			data, _ = json.Marshal(envelope)
+			signed, _ := ParseSigningEnvelope(data)
+			if err := collector.Add(signed); err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+			if err := collector.Check(signers); err != nil {
+				t.Errorf("Check() error = %v", err)
+			}
+
+			tampered := *signed
+			tampered.Nonce++
+			if err := collector.Add(&tampered); err == nil {
+				t.Errorf("Add(other nonce) error = nil, want error")
+			}
+
+			res, err := SubmitMultiSig[DefaultExchangeResponse](api, collector)
+			if err != nil {
+				t.Fatalf("SubmitMultiSig() error = %v", err)
+			}
+			if res.Status != "ok" || *actionType != "multiSig" {
+				t.Errorf("SubmitMultiSig() = %+v for %s", res, *actionType)
+			}
+			received := collector.Action()
+			if len(received.Signatures) != 2 {
+				t.Errorf("Signatures = %v, want 2", received.Signatures)
+			}
+			if received.Payload.MultiSigUser != strings.ToLower(treasury) || received.Payload.OuterSigner != signers.AuthorizedUsers[0] {
+				t.Errorf("payload = %+v", received.Payload)
+			}
+			var inner map[string]any
+			data, _ = json.Marshal(received.Payload.Action)
+			json.Unmarshal(data, &inner)
+			if inner["type"] != action.ActionType() {
+				t.Errorf("inner action = %v, want %s", inner, action.ActionType())
+			}
+		})
+	}
+
+	collector, _ := api.NewMultiSigCollector(treasury, actions[0], time.Hour)
+	stranger, _ := NewPKeyManager("2222222222222222222222222222222222222222222222222222222222222222")
+	envelope := collector.Envelope()
+	envelope.Sign(stranger, now)
+	collector.Add(envelope)
+	collector.SignOuter(api)
+	if err := collector.Check(signers); err == nil {
+		t.Errorf("Check(unauthorized signer) error = nil, want error")
+	}
+	now = now.Add(2 * time.Hour)
+	if _, err := SubmitMultiSig[DefaultExchangeResponse](api, collector); !errors.Is(err, ErrEnvelopeExpired) {
+		t.Errorf("SubmitMultiSig(expired) error = %v, want ErrEnvelopeExpired", err)
+	}
+}
+
+func TestExchangeAPI_ConvertToMultiSigUser(t *testing.T) {
+	var action ConvertToMultiSigUserAction
+	var api *ExchangeAPI
+	api = GetMockExchangeAPI(t, func(request ExchangeRequest) any {
+		if err := VerifySigner(request, false, api.Signer().Address()); err != nil {
+			t.Errorf("VerifySigner() error = %v", err)
+		}
+		json.Unmarshal(request.Action.(json.RawMessage), &action)
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	users := []string{"0x00000000000000000000000000000000000000BB", "0x00000000000000000000000000000000000000aa"}
+	if _, err := api.ConvertToMultiSigUser(users, 2); err != nil {
+		t.Fatalf("ConvertToMultiSigUser() error = %v", err)
+	}
+	want := `{"authorizedUsers":["0x00000000000000000000000000000000000000aa","0x00000000000000000000000000000000000000bb"],"threshold":2}`
+	if action.Type != "convertToMultiSigUser" || action.Signers != want {
+		t.Errorf("action = %+v, want signers %s", action, want)
+	}
+	if _, err := api.ConvertToMultiSigUser(users, 3); err == nil {
+		t.Errorf("ConvertToMultiSigUser(threshold 3) error = nil, want error")
+	}
+}
+
+func TestInfoAPI_GetMultiSigSigners(t *testing.T) {
+	api := GetMockInfoAPI(t, func(request InfoRequest) any {
+		if request.Typez != "userToMultiSigSigners" {
+			t.Errorf("type = %s, want userToMultiSigSigners", request.Typez)
+		}
+		if request.User == "0x0" {
+			return nil
+		}
+		return json.RawMessage(`{"authorizedUsers":["0x00000000000000000000000000000000000000aa"],"threshold":1}`)
+	})
+	signers, err := api.GetMultiSigSigners("0x1")
+	if err != nil {
+		t.Fatalf("GetMultiSigSigners() error = %v", err)
+	}
+	if signers == nil || len(signers.AuthorizedUsers) != 1 || signers.Threshold != 1 {
+		t.Errorf("GetMultiSigSigners() = %+v", signers)
+	}
+	if signers, err := api.GetMultiSigSigners("0x0"); err != nil || signers != nil {
+		t.Errorf("GetMultiSigSigners(normal user) = %+v, %v, want nil", signers, err)
+	}
+}
@@ -0,0 +1,191 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NonceStore persists the last nonce used by each signer, so nonces stay monotonic across restarts.
+type NonceStore interface {
+	// Load returns the last nonce saved for the signer, 0 if there is none
+	Load(signer common.Address) (uint64, error)
+	// Save stores the last nonce used by the signer
+	Save(signer common.Address, nonce uint64) error
+}
+
+// MemoryNonceStore keeps the nonces in memory. They are lost on restart.
+type MemoryNonceStore struct {
+	mu     sync.Mutex
+	nonces map[common.Address]uint64
+}
+
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{nonces: make(map[common.Address]uint64)}
+}
+
+func (store *MemoryNonceStore) Load(signer common.Address) (uint64, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.nonces[signer], nil
+}
+
+func (store *MemoryNonceStore) Save(signer common.Address, nonce uint64) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.nonces[signer] = nonce
+	return nil
+}
+
+// FileNonceStore keeps the nonces of all signers in a JSON file.
+// The file must not be shared by several processes.
+type FileNonceStore struct {
+	mu     sync.Mutex
+	path   string
+	nonces map[string]uint64
+}
+
+// NewFileNonceStore loads the nonces from path. The file is created on the first Save.
+func NewFileNonceStore(path string) (*FileNonceStore, error) {
+	store := &FileNonceStore{path: path, nonces: make(map[string]uint64)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &store.nonces); err != nil {
+		return nil, fmt.Errorf("invalid nonce store %s: %w", path, err)
+	}
+	return store, nil
+}
+
+func (store *FileNonceStore) Load(signer common.Address) (uint64, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.nonces[signer.Hex()], nil
+}
+
+func (store *FileNonceStore) Save(signer common.Address, nonce uint64) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.nonces[signer.Hex()] = nonce
+	data, err := json.Marshal(store.nonces)
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first, so a crash never leaves a truncated store
+	tmp := store.path + ".tmp"
+	if err := os.MkdirAll(filepath.Dir(store.path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, store.path)
+}
+
+// NonceManager hands out nonces per signer.
+//
+// Hyperliquid keeps the 100 highest nonces of each signer and only accepts a new nonce
+// if it's higher than the smallest of them and close to the server time.
+// NonceManager returns the server time in milliseconds (local time corrected by the estimated clock offset),
+// or the last nonce + 1 if that isn't higher, so the nonces of each signer are strictly increasing.
+// The last nonce of each signer is saved in the NonceStore.
+type NonceManager struct {
+	mu        sync.Mutex
+	store     NonceStore
+	clock     func() time.Time
+	offset    time.Duration // Estimated server time - local time
+	hasOffset bool          // True once the offset was estimated
+	last      map[common.Address]uint64
+}
+
+// Nonce manager of the clients without their own, see DefaultNonceManager
+var defaultNonceManager = NewNonceManager(NewMemoryNonceStore())
+
+// DefaultNonceManager returns the process-wide nonce manager used by every client unless SetNonceManager is called.
+// Sharing it keeps the nonces of a signer increasing when several clients sign with it,
+// and lets the Date headers of the info requests update the clock offset of the exchange requests.
+func DefaultNonceManager() *NonceManager {
+	return defaultNonceManager
+}
+
+// NewNonceManager creates a nonce manager that saves the nonces in store.
+// Use NewMemoryNonceStore() if the nonces don't need to survive restarts.
+func NewNonceManager(store NonceStore) *NonceManager {
+	return &NonceManager{
+		store: store,
+		clock: time.Now,
+		last:  make(map[common.Address]uint64),
+	}
+}
+
+// SetClock sets the function returning the local time, e.g. a fixed time in tests.
+func (nm *NonceManager) SetClock(clock func() time.Time) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	nm.clock = clock
+}
+
+// ClockOffset returns the estimated difference between the server time and the local time.
+func (nm *NonceManager) ClockOffset() time.Duration {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	return nm.offset
+}
+
+// Next returns the next nonce of the signer and saves it in the store.
+func (nm *NonceManager) Next(signer common.Address) (uint64, error) {
+	nm.mu.Lock()
+	clock := nm.clock
+	nm.mu.Unlock()
+	return nm.NextAt(signer, clock())
+}
+
+// NextAt is like Next with the local time now instead of the clock of the manager.
+// Clients sharing a manager pass their own clock, so SetClock of one client doesn't change the others.
+func (nm *NonceManager) NextAt(signer common.Address, now time.Time) (uint64, error) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	last, ok := nm.last[signer]
+	if !ok {
+		var err error
+		if last, err = nm.store.Load(signer); err != nil {
+			return 0, err
+		}
+	}
+	nonce := uint64(now.Add(nm.offset).UnixMilli())
+	if nonce <= last {
+		nonce = last + 1
+	}
+	if err := nm.store.Save(signer, nonce); err != nil {
+		return 0, err
+	}
+	nm.last[signer] = nonce
+	return nonce, nil
+}
+
+// ObserveServerTime updates the clock offset with the server time of a response
+// (e.g. its Date header) that was requested at sent and received at received, in local time.
+// The server time is assumed to be in the middle of the round trip.
+// Samples are smoothed, because the Date header only has a resolution of one second.
+func (nm *NonceManager) ObserveServerTime(serverTime time.Time, sent time.Time, received time.Time) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	local := sent.Add(received.Sub(sent) / 2)
+	sample := serverTime.Sub(local)
+	if !nm.hasOffset {
+		nm.offset = sample
+		nm.hasOffset = true
+		return
+	}
+	nm.offset += (sample - nm.offset) / 5
+}
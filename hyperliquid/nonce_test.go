@@ -0,0 +1,147 @@
+package hyperliquid
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestNonceManager_Next(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+	nm := NewNonceManager(NewMemoryNonceStore())
+	nm.SetClock(func() time.Time { return now })
+	alice := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	bob := common.HexToAddress("0x0000000000000000000000000000000000000002")
+
+	for i, want := range []uint64{1700000000000, 1700000000001, 1700000000002} {
+		if got, _ := nm.Next(alice); got != want {
+			t.Errorf("Next(alice) #%d = %d, want %d", i, got, want)
+		}
+	}
+	if got, _ := nm.Next(bob); got != 1700000000000 {
+		t.Errorf("Next(bob) = %d, want 1700000000000", got)
+	}
+	now = now.Add(time.Second)
+	if got, _ := nm.Next(alice); got != 1700000001000 {
+		t.Errorf("Next(alice) after 1s = %d, want 1700000001000", got)
+	}
+}
+
+func TestNonceManager_FileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nonces.json")
+	signer := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	now := time.UnixMilli(1700000000000)
+	clock := func() time.Time { return now }
+
+	store, err := NewFileNonceStore(path)
+	if err != nil {
+		t.Fatalf("NewFileNonceStore() error = %v", err)
+	}
+	nm := NewNonceManager(store)
+	nm.SetClock(clock)
+	nm.Next(signer)
+	nm.Next(signer)
+
+	// After a restart with a clock that went backwards, the nonces keep increasing
+	store, err = NewFileNonceStore(path)
+	if err != nil {
+		t.Fatalf("NewFileNonceStore() error = %v", err)
+	}
+	if got, _ := store.Load(signer); got != 1700000000001 {
+		t.Errorf("Load() = %d, want 1700000000001", got)
+	}
+	now = now.Add(-time.Minute)
+	nm = NewNonceManager(store)
+	nm.SetClock(clock)
+	if got, _ := nm.Next(signer); got != 1700000000002 {
+		t.Errorf("Next() after restart = %d, want 1700000000002", got)
+	}
+}
+
+func TestNonceManager_ObserveServerTime(t *testing.T) {
+	local := time.UnixMilli(1700000000000)
+	nm := NewNonceManager(NewMemoryNonceStore())
+	nm.SetClock(func() time.Time { return local })
+
+	// Server is 2s ahead, the round trip takes 100ms
+	nm.ObserveServerTime(local.Add(2050*time.Millisecond), local, local.Add(100*time.Millisecond))
+	if got := nm.ClockOffset(); got != 2*time.Second {
+		t.Errorf("ClockOffset() = %s, want 2s", got)
+	}
+	if got, _ := nm.Next(common.Address{}); got != 1700000002000 {
+		t.Errorf("Next() = %d, want 1700000002000", got)
+	}
+	// Later samples are smoothed
+	nm.ObserveServerTime(local.Add(7*time.Second), local, local)
+	if got := nm.ClockOffset(); got != 3*time.Second {
+		t.Errorf("ClockOffset() = %s, want 3s", got)
+	}
+}
+
+func TestClient_NonceFromDateHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", "Tue, 14 Nov 2023 22:13:30 GMT")
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	// The local clock is 10 minutes late
+	local := time.Date(2023, 11, 14, 22, 3, 30, 0, time.UTC)
+	client := NewClient(false)
+	client.baseUrl = server.URL
+	client.SetNonceManager(NewNonceManager(NewMemoryNonceStore()))
+	client.SetClock(func() time.Time { return local })
+	if _, err := client.Request("info", map[string]string{"type": "meta"}); err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+	want := 10*time.Minute + 500*time.Millisecond
+	if got := client.NonceManager().ClockOffset(); got != want {
+		t.Errorf("ClockOffset() = %s, want %s", got, want)
+	}
+	if got, _ := client.NonceManager().NextAt(common.Address{}, local); got != uint64(local.Add(want).UnixMilli()) {
+		t.Errorf("Next() = %d, want %d", got, local.Add(want).UnixMilli())
+	}
+
+	// A client with its own clock doesn't change the offset of the shared manager
+	shared := NewClient(false)
+	shared.baseUrl = server.URL
+	shared.SetClock(func() time.Time { return local })
+	offset := DefaultNonceManager().ClockOffset()
+	if _, err := shared.Request("info", map[string]string{"type": "meta"}); err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+	if got := DefaultNonceManager().ClockOffset(); got != offset {
+		t.Errorf("DefaultNonceManager().ClockOffset() = %s, want %s", got, offset)
+	}
+}
+
+func TestClient_SharedNonceManager(t *testing.T) {
+	if NewClient(true).NonceManager() != DefaultNonceManager() || NewClient(false).NonceManager() != DefaultNonceManager() {
+		t.Errorf("NewClient() doesn't use DefaultNonceManager()")
+	}
+
+	// The info API of an exchange follows its nonce manager and clock
+	info := NewInfoAPI(false)
+	api := &ExchangeAPI{Client: *NewClient(false), infoAPI: info}
+	nonces := NewNonceManager(NewMemoryNonceStore())
+	api.SetNonceManager(nonces)
+	if api.NonceManager() != nonces || info.NonceManager() != nonces {
+		t.Errorf("SetNonceManager() didn't set the manager of the info API")
+	}
+
+	// Clients sharing a manager keep their own clock
+	now := time.UnixMilli(1700000000000)
+	api.SetClock(func() time.Time { return now })
+	other := NewClient(false)
+	other.SetNonceManager(nonces)
+	if got, _ := api.nextNonce(); got != 1700000000000 {
+		t.Errorf("nextNonce() = %d, want 1700000000000", got)
+	}
+	if info.clock().UnixMilli() != now.UnixMilli() || other.clock().UnixMilli() == now.UnixMilli() {
+		t.Errorf("SetClock() changed the clock of another client or not the one of the info API")
+	}
+}
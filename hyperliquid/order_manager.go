@@ -0,0 +1,538 @@
+package hyperliquid
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrOrderNotTracked is returned by OrderManager.Wait for an order the manager doesn't track
+var ErrOrderNotTracked = errors.New("order is not tracked")
+
+// OrderState is the state of an order tracked by OrderManager.
+//
+//	pending -> resting -> partiallyFilled -> filled
+//	   |          |             |
+//	   |          +-> triggered +---------> cancelled
+//	   +------------------------------------> rejected
+type OrderState string
+
+const (
+	OrderStatePending         OrderState = "pending"         // Sent, not acknowledged by the exchange yet
+	OrderStateResting         OrderState = "resting"         // On the book or waiting for its trigger, nothing filled
+	OrderStatePartiallyFilled OrderState = "partiallyFilled" // On the book, partially filled
+	OrderStateTriggered       OrderState = "triggered"       // Trigger order whose trigger price was reached
+	OrderStateFilled          OrderState = "filled"
+	OrderStateCancelled       OrderState = "cancelled"
+	OrderStateRejected        OrderState = "rejected"
+)
+
+// IsTerminal returns true if the order can't change anymore (filled, cancelled or rejected)
+func (state OrderState) IsTerminal() bool {
+	return state == OrderStateFilled || state == OrderStateCancelled || state == OrderStateRejected
+}
+
+// TrackedOrder is a snapshot of an order tracked by OrderManager
+type TrackedOrder struct {
+	Oid       int64 // 0 while the order is pending
+	Cloid     string
+	Coin      string
+	IsBuy     bool
+	OrigSz    Decimal
+	FilledSz  Decimal
+	AvgPx     Decimal // Average fill price, zero if nothing was filled
+	State     OrderState
+	Status    OrderStatus // Last status reported by the exchange, e.g. "marginCanceled"
+	Error     string      // Error of the order response if the order was rejected
+	UpdatedAt int64       // Time of the last update in milliseconds
+}
+
+// managedOrder is a tracked order with its fills
+type managedOrder struct {
+	TrackedOrder
+	fills   map[int64]bool // Trade ids of the applied fills
+	fillSz  Decimal
+	fillNtl Decimal
+	done    chan struct{} // Closed when the order reaches a terminal state
+}
+
+func newManagedOrder(request OrderRequest) *managedOrder {
+	return &managedOrder{
+		TrackedOrder: TrackedOrder{
+			Cloid:  request.Cloid,
+			Coin:   request.Coin,
+			IsBuy:  request.IsBuy,
+			OrigSz: request.Sz,
+			State:  OrderStatePending,
+		},
+		fills: make(map[int64]bool),
+		done:  make(chan struct{}),
+	}
+}
+
+// setState moves the order to state, terminal states are never left
+func (order *managedOrder) setState(state OrderState) {
+	if order.State.IsTerminal() || order.State == state {
+		return
+	}
+	order.State = state
+	if state.IsTerminal() {
+		close(order.done)
+	}
+}
+
+// setFilledSz raises the filled size, sizes reported by the exchange and by the fills may arrive in any order
+func (order *managedOrder) setFilledSz(filledSz Decimal) {
+	if filledSz.GreaterThan(order.FilledSz) {
+		order.FilledSz = filledSz
+	}
+}
+
+// applyFill adds a fill to the filled size and the average price, a fill is only applied once
+func (order *managedOrder) applyFill(fill OrderFill) {
+	if order.fills[fill.Tid] {
+		return
+	}
+	order.fills[fill.Tid] = true
+	order.fillSz = order.fillSz.Add(fill.Sz)
+	order.fillNtl = order.fillNtl.Add(fill.Sz.Mul(fill.Px))
+	order.AvgPx = order.fillNtl.Div(order.fillSz)
+	order.setFilledSz(order.fillSz)
+	order.UpdatedAt = max(order.UpdatedAt, fill.Time)
+	if order.OrigSz.IsPositive() && order.FilledSz.GreaterThanOrEqual(order.OrigSz) {
+		order.setState(OrderStateFilled)
+	} else {
+		order.setState(OrderStatePartiallyFilled)
+	}
+}
+
+// applyUpdate applies an order update of the websocket or of orderStatus
+func (order *managedOrder) applyUpdate(update HistoricalOrder) {
+	if !update.Order.OrigSz.IsZero() {
+		order.OrigSz = update.Order.OrigSz
+		order.setFilledSz(update.Order.OrigSz.Sub(update.Order.Sz))
+	}
+	order.Status = update.Status
+	order.UpdatedAt = max(order.UpdatedAt, update.StatusTimestamp)
+	switch {
+	case update.Status == OrderStatusOpen && order.FilledSz.IsPositive():
+		order.setState(OrderStatePartiallyFilled)
+	case update.Status == OrderStatusOpen:
+		order.setState(OrderStateResting)
+	case update.Status == OrderStatusFilled:
+		order.setFilledSz(order.OrigSz)
+		order.setState(OrderStateFilled)
+	case update.Status == OrderStatusTriggered:
+		order.setState(OrderStateTriggered)
+	case update.Status.IsRejected():
+		order.setState(OrderStateRejected)
+	case update.Status.IsCanceled():
+		order.setState(OrderStateCancelled)
+	}
+}
+
+// OrderManager tracks the orders it submits from the order response to a terminal state.
+//
+// The manager has no connection of its own: feed it the messages of the orderUpdates and userFills
+// websocket channels with HandleMessage, and run ReconcileEvery to catch up on missed messages
+// with the open orders and the order status of the REST API.
+//
+//	manager := NewOrderManager(exchangeAPI)
+//	go manager.ReconcileEvery(ctx, 30*time.Second)
+//	// for each websocket message: manager.HandleMessage(message)
+//	orders, err := manager.Submit(requests, GroupingNa, false)
+//	order, err := manager.Wait(ctx, orders[0].Oid)
+type OrderManager struct {
+	mu       sync.Mutex
+	exchange *ExchangeAPI
+	byOid    map[int64]*managedOrder
+	byCloid  map[string]*managedOrder
+	inflight int // Number of Submit calls waiting for their response
+	// Messages of unknown orders received while orders are in flight,
+	// the websocket can be faster than the order response
+	orphanUpdates map[int64]HistoricalOrder
+	orphanFills   map[int64][]OrderFill
+}
+
+// NewOrderManager creates an order manager that submits the orders with exchange.
+// The orders are reconciled with the account of exchange, or its vault if one is set (see SetVaultAddress).
+func NewOrderManager(exchange *ExchangeAPI) *OrderManager {
+	return &OrderManager{
+		exchange:      exchange,
+		byOid:         make(map[int64]*managedOrder),
+		byCloid:       make(map[string]*managedOrder),
+		orphanUpdates: make(map[int64]HistoricalOrder),
+		orphanFills:   make(map[int64][]OrderFill),
+	}
+}
+
+// Helper function to get the user whose orders are managed
+func (om *OrderManager) user() string {
+	if om.exchange.vaultAddress != "" {
+		return om.exchange.vaultAddress
+	}
+	return om.exchange.AccountAddress()
+}
+
+// Submit places the orders with BulkOrders and tracks them.
+// Orders with a cloid are tracked before they are sent, so their updates are never missed.
+// If BulkOrders fails the orders are not tracked.
+func (om *OrderManager) Submit(requests []OrderRequest, grouping Grouping, isSpot bool) ([]TrackedOrder, error) {
+	om.mu.Lock()
+	om.inflight++
+	pending := make([]*managedOrder, len(requests))
+	for i, request := range requests {
+		if request.Cloid != "" {
+			pending[i] = newManagedOrder(request)
+			om.byCloid[request.Cloid] = pending[i]
+		}
+	}
+	om.mu.Unlock()
+
+	response, err := om.exchange.BulkOrders(requests, grouping, isSpot)
+
+	om.mu.Lock()
+	defer om.mu.Unlock()
+	om.inflight--
+	defer om.dropOrphans()
+	if err != nil {
+		for _, order := range pending {
+			if order != nil && om.byCloid[order.Cloid] == order {
+				delete(om.byCloid, order.Cloid)
+			}
+		}
+		return nil, err
+	}
+	return om.track(requests, response, pending), nil
+}
+
+// Track tracks orders placed without Submit, e.g. with SubmitSigned, from their requests and response.
+// Updates received before Track are only caught up on by Reconcile.
+// Orders without an oid or a cloid in the response (e.g. waiting TP/SL orders) can't be tracked and stay pending.
+func (om *OrderManager) Track(requests []OrderRequest, response *OrderResponse) []TrackedOrder {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+	return om.track(requests, response, make([]*managedOrder, len(requests)))
+}
+
+// Helper function to track orders from their response, pending holds the orders already tracked by cloid
+func (om *OrderManager) track(requests []OrderRequest, response *OrderResponse, pending []*managedOrder) []TrackedOrder {
+	now := om.exchange.clock().UnixMilli()
+	statuses := response.Response.Data.Statuses
+	orders := make([]TrackedOrder, len(requests))
+	for i, request := range requests {
+		order := pending[i]
+		if order == nil {
+			order = newManagedOrder(request)
+		}
+		order.UpdatedAt = max(order.UpdatedAt, now)
+		if i >= len(statuses) {
+			order.Error = fmt.Sprintf("no status for order %d in response", i)
+			order.setState(OrderStateRejected)
+			orders[i] = order.TrackedOrder
+			continue
+		}
+		status := statuses[i]
+		switch {
+		case status.Error != "":
+			order.Error = status.Error
+			order.setState(OrderStateRejected)
+		case status.Resting.OrderId != 0:
+			order.Oid = int64(status.Resting.OrderId)
+			// The websocket may have moved the order further already
+			if order.State == OrderStatePending {
+				order.setState(OrderStateResting)
+			}
+		case status.Filled.OrderId != 0:
+			order.Oid = int64(status.Filled.OrderId)
+			order.setFilledSz(status.Filled.TotalSz)
+			if order.fillSz.IsZero() {
+				order.AvgPx = status.Filled.AvgPx
+			}
+			order.setState(OrderStateFilled)
+		}
+		if order.Cloid != "" {
+			om.byCloid[order.Cloid] = order
+		}
+		if order.Oid != 0 {
+			om.byOid[order.Oid] = order
+			om.applyOrphans(order)
+		}
+		orders[i] = order.TrackedOrder
+	}
+	return orders
+}
+
+// Helper function to apply the messages received for an order before its oid was known
+func (om *OrderManager) applyOrphans(order *managedOrder) {
+	if update, ok := om.orphanUpdates[order.Oid]; ok {
+		order.applyUpdate(update)
+		delete(om.orphanUpdates, order.Oid)
+	}
+	for _, fill := range om.orphanFills[order.Oid] {
+		order.applyFill(fill)
+	}
+	delete(om.orphanFills, order.Oid)
+}
+
+// Helper function to forget the messages of unknown orders once no order is in flight
+func (om *OrderManager) dropOrphans() {
+	if om.inflight > 0 {
+		return
+	}
+	clear(om.orphanUpdates)
+	clear(om.orphanFills)
+}
+
+// Helper function to find a tracked order by oid, or by cloid for an order whose oid is not known yet
+func (om *OrderManager) find(oid int64, cloid string) *managedOrder {
+	if order, ok := om.byOid[oid]; ok {
+		return order
+	}
+	if cloid == "" {
+		return nil
+	}
+	order, ok := om.byCloid[cloid]
+	if !ok {
+		return nil
+	}
+	if order.Oid == 0 && oid != 0 {
+		order.Oid = oid
+		om.byOid[oid] = order
+	}
+	return order
+}
+
+// HandleOrderUpdates applies the data of an orderUpdates websocket message. Updates of untracked orders are ignored.
+func (om *OrderManager) HandleOrderUpdates(updates []HistoricalOrder) {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+	for _, update := range updates {
+		order := om.find(update.Order.Oid, update.Order.Cloid)
+		if order == nil {
+			if om.inflight > 0 {
+				om.orphanUpdates[update.Order.Oid] = update
+			}
+			continue
+		}
+		order.applyUpdate(update)
+	}
+}
+
+// HandleFills applies the fills of a userFills websocket message. Fills of untracked orders are ignored,
+// fills already applied (e.g. in the snapshot sent on subscription) are not applied again.
+func (om *OrderManager) HandleFills(fills []OrderFill) {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+	for _, fill := range fills {
+		order := om.find(int64(fill.Oid), fill.Cloid)
+		if order == nil {
+			if om.inflight > 0 {
+				om.orphanFills[int64(fill.Oid)] = append(om.orphanFills[int64(fill.Oid)], fill)
+			}
+			continue
+		}
+		order.applyFill(fill)
+	}
+}
+
+// HandleMessage decodes a websocket message and applies it if it's from the orderUpdates or userFills channel.
+// Messages of other channels are ignored.
+func (om *OrderManager) HandleMessage(data []byte) error {
+	var message WsMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return err
+	}
+	switch message.Channel {
+	case "orderUpdates":
+		var updates []HistoricalOrder
+		if err := json.Unmarshal(message.Data, &updates); err != nil {
+			return fmt.Errorf("invalid orderUpdates message: %w", err)
+		}
+		om.HandleOrderUpdates(updates)
+	case "userFills":
+		var fills WsUserFills
+		if err := json.Unmarshal(message.Data, &fills); err != nil {
+			return fmt.Errorf("invalid userFills message: %w", err)
+		}
+		om.HandleFills(fills.Fills)
+	}
+	return nil
+}
+
+// Reconcile updates the tracked orders that are not in a terminal state from the REST API.
+// Open orders are updated from GetOpenOrders, the others are looked up with GetOrderStatus.
+func (om *OrderManager) Reconcile() error {
+	user := om.user()
+	openOrders, err := om.exchange.infoAPI.GetOpenOrders(user)
+	if err != nil {
+		return err
+	}
+	now := om.exchange.clock().UnixMilli()
+	open := make(map[int64]Order, len(*openOrders))
+	for _, order := range *openOrders {
+		open[order.Oid] = order
+	}
+
+	// Orders that are no longer open, or pending ones that only have a cloid
+	var missing []TrackedOrder
+	om.mu.Lock()
+	for _, order := range om.active() {
+		if openOrder, ok := open[order.Oid]; ok && order.Oid != 0 {
+			order.applyUpdate(HistoricalOrder{Order: openOrder, Status: OrderStatusOpen, StatusTimestamp: now})
+			continue
+		}
+		if order.Oid != 0 || order.Cloid != "" {
+			missing = append(missing, order.TrackedOrder)
+		}
+	}
+	om.mu.Unlock()
+
+	for _, order := range missing {
+		var status *HistoricalOrder
+		if order.Oid != 0 {
+			status, err = om.exchange.infoAPI.GetOrderStatus(user, order.Oid)
+		} else {
+			status, err = om.exchange.infoAPI.GetOrderStatusByCloid(user, order.Cloid)
+		}
+		if err != nil {
+			return err
+		}
+		if status == nil {
+			continue
+		}
+		om.HandleOrderUpdates([]HistoricalOrder{*status})
+	}
+	return nil
+}
+
+// Helper function to list the tracked orders that are not in a terminal state
+func (om *OrderManager) active() []*managedOrder {
+	seen := make(map[*managedOrder]bool)
+	var orders []*managedOrder
+	add := func(order *managedOrder) {
+		if !seen[order] && !order.State.IsTerminal() {
+			seen[order] = true
+			orders = append(orders, order)
+		}
+	}
+	for _, order := range om.byOid {
+		add(order)
+	}
+	for _, order := range om.byCloid {
+		add(order)
+	}
+	return orders
+}
+
+// ReconcileEvery runs Reconcile every interval until ctx is done.
+// Errors are logged in debug mode and retried at the next interval.
+func (om *OrderManager) ReconcileEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := om.Reconcile(); err != nil {
+				om.exchange.debug("Error reconciling orders: %s", err)
+			}
+		}
+	}
+}
+
+// Get returns the tracked order with the oid
+func (om *OrderManager) Get(oid int64) (TrackedOrder, bool) {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+	order, ok := om.byOid[oid]
+	if !ok {
+		return TrackedOrder{}, false
+	}
+	return order.TrackedOrder, true
+}
+
+// GetByCloid returns the tracked order with the cloid
+func (om *OrderManager) GetByCloid(cloid string) (TrackedOrder, bool) {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+	order, ok := om.byCloid[cloid]
+	if !ok {
+		return TrackedOrder{}, false
+	}
+	return order.TrackedOrder, true
+}
+
+// Orders returns all the tracked orders sorted by oid, pending orders without an oid first
+func (om *OrderManager) Orders() []TrackedOrder {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+	seen := make(map[*managedOrder]bool)
+	var orders []TrackedOrder
+	for _, order := range om.byOid {
+		seen[order] = true
+		orders = append(orders, order.TrackedOrder)
+	}
+	for _, order := range om.byCloid {
+		if !seen[order] {
+			orders = append(orders, order.TrackedOrder)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].Oid < orders[j].Oid
+	})
+	return orders
+}
+
+// Forget stops tracking the order with the oid, e.g. once its terminal state was handled
+func (om *OrderManager) Forget(oid int64) {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+	order, ok := om.byOid[oid]
+	if !ok {
+		return
+	}
+	delete(om.byOid, oid)
+	if order.Cloid != "" && om.byCloid[order.Cloid] == order {
+		delete(om.byCloid, order.Cloid)
+	}
+}
+
+// Wait blocks until the order with the oid reaches a terminal state or ctx is done.
+// It returns the last state of the order with the error of ctx if ctx is done first.
+func (om *OrderManager) Wait(ctx context.Context, oid int64) (TrackedOrder, error) {
+	om.mu.Lock()
+	order, ok := om.byOid[oid]
+	om.mu.Unlock()
+	return om.wait(ctx, order, ok)
+}
+
+// WaitCloid is like Wait for the order with the cloid
+func (om *OrderManager) WaitCloid(ctx context.Context, cloid string) (TrackedOrder, error) {
+	om.mu.Lock()
+	order, ok := om.byCloid[cloid]
+	om.mu.Unlock()
+	return om.wait(ctx, order, ok)
+}
+
+// Helper function to wait for the terminal state of an order
+func (om *OrderManager) wait(ctx context.Context, order *managedOrder, ok bool) (TrackedOrder, error) {
+	if !ok {
+		return TrackedOrder{}, ErrOrderNotTracked
+	}
+	select {
+	case <-order.done:
+	case <-ctx.Done():
+	}
+	om.mu.Lock()
+	defer om.mu.Unlock()
+	if order.State.IsTerminal() {
+		return order.TrackedOrder, nil
+	}
+	return order.TrackedOrder, ctx.Err()
+}
@@ -0,0 +1,199 @@
+package hyperliquid
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func getTestOrderManager(t *testing.T, statuses string, onSubmit func(manager *OrderManager)) *OrderManager {
+	var manager *OrderManager
+	api := GetMockExchangeAPI(t, func(request ExchangeRequest) any {
+		if onSubmit != nil {
+			onSubmit(manager)
+		}
+		return json.RawMessage(fmt.Sprintf(`{"status":"ok","response":{"type":"order","data":{"statuses":%s}}}`, statuses))
+	})
+	api.SetClock(func() time.Time { return time.UnixMilli(500) })
+	manager = NewOrderManager(api)
+	return manager
+}
+
+func testOrderRequests() []OrderRequest {
+	orderType := OrderType{Limit: &LimitOrderType{Tif: TifGtc}}
+	withCloid := NewOrderRequest("ETH", -0.2, 2600, orderType, false)
+	withCloid.Cloid = "0x00000000000000000000000000000001"
+	return []OrderRequest{
+		NewOrderRequest("ETH", 0.1, 2500, orderType, false),
+		withCloid,
+		NewOrderRequest("ETH", 0.1, 1, orderType, false),
+	}
+}
+
+func TestOrderManager_Lifecycle(t *testing.T) {
+	manager := getTestOrderManager(t, `[{"resting":{"oid":11}},{"resting":{"oid":12,"cloid":"0x00000000000000000000000000000001"}},{"error":"Order price too far from oracle."}]`, nil)
+	orders, err := manager.Submit(testOrderRequests(), GroupingNa, false)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	want := []struct {
+		oid   int64
+		state OrderState
+	}{{11, OrderStateResting}, {12, OrderStateResting}, {0, OrderStateRejected}}
+	for i, order := range orders {
+		if order.Oid != want[i].oid || order.State != want[i].state {
+			t.Errorf("Submit()[%d] = oid %d %s, want oid %d %s", i, order.Oid, order.State, want[i].oid, want[i].state)
+		}
+	}
+	if orders[2].Error != "Order price too far from oracle." {
+		t.Errorf("Submit()[2].Error = %q", orders[2].Error)
+	}
+
+	done := make(chan TrackedOrder)
+	go func() {
+		order, err := manager.Wait(context.Background(), 11)
+		if err != nil {
+			t.Errorf("Wait() error = %v", err)
+		}
+		done <- order
+	}()
+
+	messages := []string{
+		`{"channel":"userFills","data":{"user":"0x1","fills":[{"coin":"ETH","px":"2500","sz":"0.04","side":"B","oid":11,"tid":1,"time":1000}]}}`,
+		// Replayed in the snapshot of a new subscription
+		`{"channel":"userFills","data":{"isSnapshot":true,"user":"0x1","fills":[{"coin":"ETH","px":"2500","sz":"0.04","side":"B","oid":11,"tid":1,"time":1000}]}}`,
+		`{"channel":"orderUpdates","data":[{"order":{"coin":"ETH","side":"A","limitPx":"2600","sz":"0.2","oid":12,"origSz":"0.2","cloid":"0x00000000000000000000000000000001"},"status":"reduceOnlyCanceled","statusTimestamp":1500}]}`,
+		`{"channel":"allMids","data":{"mids":{"ETH":"2500"}}}`,
+	}
+	for _, message := range messages {
+		if err := manager.HandleMessage([]byte(message)); err != nil {
+			t.Fatalf("HandleMessage() error = %v", err)
+		}
+	}
+	order, _ := manager.Get(11)
+	if order.State != OrderStatePartiallyFilled || !order.FilledSz.Equal(MustDecimal("0.04")) {
+		t.Errorf("Get(11) = %s %s filled, want partiallyFilled 0.04", order.State, order.FilledSz)
+	}
+	cancelled, _ := manager.GetByCloid("0x00000000000000000000000000000001")
+	if cancelled.State != OrderStateCancelled || cancelled.Status != OrderStatusReduceOnlyCanceled {
+		t.Errorf("GetByCloid() = %s %s, want cancelled", cancelled.State, cancelled.Status)
+	}
+
+	manager.HandleFills([]OrderFill{{Coin: "ETH", Px: MustDecimal("2499"), Sz: MustDecimal("0.06"), Oid: 11, Tid: 2, Time: 2000}})
+	select {
+	case order = <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Wait() didn't return after the order was filled")
+	}
+	if order.State != OrderStateFilled || !order.AvgPx.Equal(MustDecimal("2499.4")) || order.UpdatedAt != 2000 {
+		t.Errorf("Wait() = %+v, want filled at 2499.4", order)
+	}
+
+	// Terminal states are never left
+	manager.HandleOrderUpdates([]HistoricalOrder{{Order: Order{Oid: 11}, Status: OrderStatusCanceled}})
+	if order, _ := manager.Get(11); order.State != OrderStateFilled {
+		t.Errorf("Get(11).State = %s after a late cancel, want filled", order.State)
+	}
+
+	manager.Forget(11)
+	if _, err := manager.Wait(context.Background(), 11); !errors.Is(err, ErrOrderNotTracked) {
+		t.Errorf("Wait() of a forgotten order error = %v, want %v", err, ErrOrderNotTracked)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := manager.WaitCloid(ctx, "0x00000000000000000000000000000001"); err != nil {
+		t.Errorf("WaitCloid() of a cancelled order error = %v", err)
+	}
+}
+
+func TestOrderManager_MessagesBeforeResponse(t *testing.T) {
+	// The websocket reports the fills before the order response arrives
+	manager := getTestOrderManager(t, `[{"filled":{"oid":21,"totalSz":"0.1","avgPx":"2500"}},{"resting":{"oid":22}},{"resting":{"oid":23}}]`, func(manager *OrderManager) {
+		manager.HandleFills([]OrderFill{{Coin: "ETH", Px: MustDecimal("2501"), Sz: MustDecimal("0.1"), Oid: 21, Tid: 5}})
+		manager.HandleOrderUpdates([]HistoricalOrder{{
+			Order:  Order{Coin: "ETH", Oid: 22, Cloid: "0x00000000000000000000000000000001", OrigSz: MustDecimal("0.2"), Sz: MustDecimal("0.15")},
+			Status: OrderStatusOpen,
+		}})
+	})
+	orders, err := manager.Submit(testOrderRequests(), GroupingNa, false)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if orders[0].State != OrderStateFilled || !orders[0].AvgPx.Equal(MustDecimal("2501")) {
+		t.Errorf("Submit()[0] = %s at %s, want filled at the fill price 2501", orders[0].State, orders[0].AvgPx)
+	}
+	if orders[1].State != OrderStatePartiallyFilled || !orders[1].FilledSz.Equal(MustDecimal("0.05")) {
+		t.Errorf("Submit()[1] = %s %s filled, want partiallyFilled 0.05", orders[1].State, orders[1].FilledSz)
+	}
+	if orders[2].State != OrderStateResting {
+		t.Errorf("Submit()[2].State = %s, want resting", orders[2].State)
+	}
+	if len(manager.orphanFills) != 0 || len(manager.orphanUpdates) != 0 {
+		t.Errorf("orphans = %v %v, want none after Submit", manager.orphanFills, manager.orphanUpdates)
+	}
+	// Messages of other orders are not kept once no order is in flight
+	manager.HandleFills([]OrderFill{{Oid: 99, Tid: 6}})
+	if len(manager.orphanFills) != 0 {
+		t.Errorf("orphanFills = %v, want none", manager.orphanFills)
+	}
+}
+
+func TestOrderManager_Reconcile(t *testing.T) {
+	manager := getTestOrderManager(t, `[{"resting":{"oid":31}},{"resting":{"oid":32}},{"resting":{"oid":33}}]`, nil)
+	manager.exchange.infoAPI = GetMockInfoAPI(t, func(request OrderStatusRequest) any {
+		switch {
+		case request.Typez == "openOrders":
+			return []Order{{Coin: "ETH", Oid: 31, OrigSz: MustDecimal("0.1"), Sz: MustDecimal("0.07")}}
+		case request.Typez == "orderStatus" && request.Oid == float64(32):
+			return OrderStatusResponse{Status: "order", Order: &HistoricalOrder{
+				Order:  Order{Coin: "ETH", Oid: 32, OrigSz: MustDecimal("0.2"), Sz: MustDecimal("0")},
+				Status: OrderStatusFilled,
+			}}
+		case request.Typez == "orderStatus":
+			return OrderStatusResponse{Status: "unknownOid"}
+		}
+		t.Errorf("unexpected request %+v", request)
+		return nil
+	})
+	if _, err := manager.Submit(testOrderRequests(), GroupingNa, false); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if err := manager.Reconcile(); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	want := map[int64]OrderState{31: OrderStatePartiallyFilled, 32: OrderStateFilled, 33: OrderStateResting}
+	for _, order := range manager.Orders() {
+		if order.State != want[order.Oid] {
+			t.Errorf("order %d State = %s, want %s", order.Oid, order.State, want[order.Oid])
+		}
+	}
+	if order, _ := manager.Get(32); !order.FilledSz.Equal(MustDecimal("0.2")) {
+		t.Errorf("Get(32).FilledSz = %s, want 0.2", order.FilledSz)
+	}
+}
+
+func TestOrderStatus_IsCanceled(t *testing.T) {
+	tests := []struct {
+		status   OrderStatus
+		canceled bool
+		rejected bool
+	}{
+		{OrderStatusCanceled, true, false},
+		{OrderStatusMarginCanceled, true, false},
+		{OrderStatusSiblingFilledCanceled, true, false},
+		{OrderStatusScheduledCancel, true, false},
+		{OrderStatusRejected, false, true},
+		{OrderStatusMinTradeNtlRejected, false, true},
+		{OrderStatusPositionFlipAtOpenInterestCapRejected, false, true},
+		{OrderStatusFilled, false, false},
+		{OrderStatusTriggered, false, false},
+	}
+	for _, tt := range tests {
+		if tt.status.IsCanceled() != tt.canceled || tt.status.IsRejected() != tt.rejected {
+			t.Errorf("%s IsCanceled() = %v IsRejected() = %v", tt.status, tt.status.IsCanceled(), tt.status.IsRejected())
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package hyperliquid
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OrderFieldError is a single rule broken by one field of one order.
+type OrderFieldError struct {
+	Index   int    // Position of the order in the validated batch
+	Coin    string // Coin of the order
+	Field   string // Field of OrderRequest, e.g. "sz", "limit_px", "order_type.trigger.triggerPx"
+	Message string
+}
+
+func (e OrderFieldError) Error() string {
+	return fmt.Sprintf("order %d (%s): %s: %s", e.Index, e.Coin, e.Field, e.Message)
+}
+
+// OrderValidationError holds every rule broken by a batch of orders.
+type OrderValidationError []OrderFieldError
+
+func (e OrderValidationError) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Error()
+	}
+	return "invalid orders: " + strings.Join(messages, "; ")
+}
+
+// ForOrder returns the errors of the order at the given index of the batch.
+func (e OrderValidationError) ForOrder(index int) []OrderFieldError {
+	var result []OrderFieldError
+	for _, fieldErr := range e {
+		if fieldErr.Index == index {
+			result = append(result, fieldErr)
+		}
+	}
+	return result
+}
+
+// ValidateOrderRequests checks the orders against the exchange rules before they are signed.
+// It returns an OrderValidationError listing every broken rule, or nil if all orders are valid.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/tick-and-lot-size
+//
+//   - The coin must be in meta (see BuildMetaMap and BuildSpotMetaMap),
+//   - Sizes must be positive and have at most szDecimals decimals,
+//   - Prices must be positive and have at most 5 significant figures and
+//     (maxDecimals - szDecimals) decimals, where maxDecimals is 6 for perps and 8 for spot.
+//     Integer prices are no exception, like OrderRequestToWire which rounds 123456 to 123460,
+//   - The order value must be at least MIN_ORDER_VALUE, unless the order is reduce only,
+//   - Exactly one of limit and trigger order type must be set, with a valid tif, tpsl and trigger price,
+//   - Spot orders can't be reduce only.
+func ValidateOrderRequests(requests []OrderRequest, meta map[string]AssetInfo, isSpot bool) error {
+	var errs OrderValidationError
+	for i, req := range requests {
+		errs = append(errs, validateOrderRequest(i, req, meta, isSpot)...)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidateModifyOrderRequests is the same as ValidateOrderRequests but for modified orders.
+func ValidateModifyOrderRequests(requests []ModifyOrderRequest, meta map[string]AssetInfo, isSpot bool) error {
+	var errs OrderValidationError
+	for i, req := range requests {
+		order := OrderRequest{
+			Coin:       req.Coin,
+			IsBuy:      req.IsBuy,
+			Sz:         req.Sz,
+			LimitPx:    req.LimitPx,
+			OrderType:  req.OrderType,
+			ReduceOnly: req.ReduceOnly,
+			Cloid:      req.Cloid,
+		}
+		errs = append(errs, validateOrderRequest(i, order, meta, isSpot)...)
+		if req.OrderId <= 0 {
+			errs = append(errs, OrderFieldError{i, req.Coin, "oid", "must be positive"})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateOrderRequest(index int, req OrderRequest, meta map[string]AssetInfo, isSpot bool) []OrderFieldError {
+	var errs []OrderFieldError
+	fail := func(field string, format string, args ...any) {
+		errs = append(errs, OrderFieldError{index, req.Coin, field, fmt.Sprintf(format, args...)})
+	}
+
+	info, ok := meta[req.Coin]
+	if !ok {
+		fail("coin", "unknown coin")
+		return errs
+	}
+	maxDecimals := PERP_MAX_DECIMALS
+	if isSpot {
+		maxDecimals = SPOT_MAX_DECIMALS
+	}
+
+	// Size
+	if !req.Sz.IsPositive() {
+		fail("sz", "must be positive, got %s", req.Sz)
+	} else if decimalPlaces(req.Sz) > info.SzDecimals {
+		fail("sz", "%s has more than %d decimals", req.Sz, info.SzDecimals)
+	}
+
+	// Price
+	if msg := validatePrice(req.LimitPx, maxDecimals, info.SzDecimals); msg != "" {
+		fail("limit_px", "%s", msg)
+	}
+
+	// Order value
+	if !req.ReduceOnly && req.Sz.IsPositive() && req.LimitPx.IsPositive() {
+		value := req.Sz.Mul(req.LimitPx)
+		if value.LessThan(NewDecimalFromFloat(MIN_ORDER_VALUE)) {
+			fail("sz", "order value %s is below the minimum of %v", value, MIN_ORDER_VALUE)
+		}
+	}
+
+	// Order type
+	limit, trigger := req.OrderType.Limit, req.OrderType.Trigger
+	switch {
+	case limit == nil && trigger == nil:
+		fail("order_type", "either limit or trigger must be set")
+	case limit != nil && trigger != nil:
+		fail("order_type", "only one of limit and trigger can be set")
+	case limit != nil:
+		if limit.Tif != TifGtc && limit.Tif != TifIoc && limit.Tif != TifAlo {
+			fail("order_type.limit.tif", "invalid tif %q, available: %s, %s, %s", limit.Tif, TifGtc, TifIoc, TifAlo)
+		}
+	case trigger != nil:
+		if trigger.TpSl != TriggerTp && trigger.TpSl != TriggerSl {
+			fail("order_type.trigger.tpsl", "invalid tpsl %q, available: %s, %s", trigger.TpSl, TriggerTp, TriggerSl)
+		}
+		triggerPx, err := NewDecimalFromString(trigger.TriggerPx)
+		if err != nil {
+			fail("order_type.trigger.triggerPx", "%q is not a number", trigger.TriggerPx)
+		} else if msg := validatePrice(triggerPx, maxDecimals, info.SzDecimals); msg != "" {
+			fail("order_type.trigger.triggerPx", "%s", msg)
+		}
+	}
+
+	// Reduce only
+	if req.ReduceOnly && isSpot {
+		fail("reduce_only", "spot orders can't be reduce only")
+	}
+
+	// Client order id is a 128 bit hex string
+	if req.Cloid != "" && (len(req.Cloid) != 34 || !strings.HasPrefix(req.Cloid, "0x") || len(HexToBytes(req.Cloid)) != 16) {
+		fail("cloid", "%q is not a 16 byte hex string", req.Cloid)
+	}
+	return errs
+}
+
+// Returns the broken price rule or an empty string if the price is valid.
+func validatePrice(px Decimal, maxDecimals, szDecimals int) string {
+	if !px.IsPositive() {
+		return fmt.Sprintf("must be positive, got %s", px)
+	}
+	if decimals := decimalPlaces(px); decimals > maxDecimals-szDecimals {
+		return fmt.Sprintf("%s has more than %d decimals", px, maxDecimals-szDecimals)
+	}
+	if sigFigs := significantFigures(px); sigFigs > 5 {
+		return fmt.Sprintf("%s has %d significant figures, max is 5", px, sigFigs)
+	}
+	return ""
+}
+
+// significantFigures returns the number of significant figures, trailing zeros excluded (e.g. 3 for 1230 and 0.0123)
+func significantFigures(x Decimal) int {
+	digits := strings.Replace(x.Abs().String(), ".", "", 1)
+	return len(strings.Trim(digits, "0"))
+}
+
+// decimalPlaces returns the number of decimals without trailing zeros (e.g. 2 for 1.250)
+func decimalPlaces(x Decimal) int {
+	s := x.String()
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return len(s) - i - 1
+	}
+	return 0
+}
@@ -0,0 +1,105 @@
+package hyperliquid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateOrderRequests(t *testing.T) {
+	meta := map[string]AssetInfo{
+		"BTC":  {SzDecimals: 5, AssetId: 0},
+		"ETH":  {SzDecimals: 4, AssetId: 1},
+		"PURR": {SzDecimals: 0, AssetId: 0},
+	}
+	gtc := OrderType{Limit: &LimitOrderType{Tif: TifGtc}}
+	sl := func(px string) OrderType {
+		return OrderType{Trigger: &TriggerOrderType{IsMarket: true, TriggerPx: px, TpSl: TriggerSl}}
+	}
+	order := func(coin string, sz string, px string, orderType OrderType, reduceOnly bool) OrderRequest {
+		return OrderRequest{Coin: coin, IsBuy: true, Sz: MustDecimal(sz), LimitPx: MustDecimal(px), OrderType: orderType, ReduceOnly: reduceOnly}
+	}
+	testCases := []struct {
+		name     string
+		order    OrderRequest
+		isSpot   bool
+		expected []string // broken fields
+	}{
+		{"valid", order("BTC", "0.001", "95001", gtc, false), false, nil},
+		{"valid integer price", order("BTC", "0.001", "123460", gtc, false), false, nil},
+		{"integer price above 5 significant figures", order("BTC", "0.001", "123456", gtc, false), false, []string{"limit_px"}},
+		{"valid trigger", order("ETH", "0.01", "2500.5", sl("2600.5"), false), false, nil},
+		{"unknown coin", order("XYZ", "1", "1", gtc, false), false, []string{"coin"}},
+		{"too many size decimals", order("BTC", "0.000011", "95000", gtc, true), false, []string{"sz"}},
+		{"zero size", order("BTC", "0", "95000", gtc, false), false, []string{"sz"}},
+		{"too many significant figures", order("ETH", "1", "2500.55", gtc, false), false, []string{"limit_px"}},
+		{"too many price decimals", order("ETH", "1000", "0.012345", gtc, false), false, []string{"limit_px"}},
+		{"spot allows more decimals", order("PURR", "1000", "0.012345", gtc, false), true, nil},
+		{"below min value", order("ETH", "0.001", "2500", gtc, false), false, []string{"sz"}},
+		{"reduce only below min value", order("ETH", "0.001", "2500", gtc, true), false, nil},
+		{"reduce only spot", order("PURR", "1000", "0.1", gtc, true), true, []string{"reduce_only"}},
+		{"no order type", order("ETH", "1", "2500", OrderType{}, false), false, []string{"order_type"}},
+		{"invalid tif", order("ETH", "1", "2500", OrderType{Limit: &LimitOrderType{Tif: "Fok"}}, false), false, []string{"order_type.limit.tif"}},
+		{"invalid trigger price", order("ETH", "1", "2500", sl("2600.55"), false), false, []string{"order_type.trigger.triggerPx"}},
+		{"trigger price not a number", order("ETH", "1", "2500", sl("abc"), false), false, []string{"order_type.trigger.triggerPx"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateOrderRequests([]OrderRequest{tc.order}, meta, tc.isSpot)
+			if tc.expected == nil {
+				if err != nil {
+					t.Errorf("ValidateOrderRequests() error = %v, want nil", err)
+				}
+				return
+			}
+			var validationErr OrderValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("ValidateOrderRequests() error = %v, want OrderValidationError", err)
+			}
+			if len(validationErr) != len(tc.expected) {
+				t.Fatalf("ValidateOrderRequests() = %v, want fields %v", validationErr, tc.expected)
+			}
+			for i, field := range tc.expected {
+				if validationErr[i].Field != field {
+					t.Errorf("field = %s, want %s (%v)", validationErr[i].Field, field, validationErr[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidateOrderRequests_PerOrder(t *testing.T) {
+	meta := map[string]AssetInfo{"ETH": {SzDecimals: 4, AssetId: 1}}
+	gtc := OrderType{Limit: &LimitOrderType{Tif: TifGtc}}
+	requests := []OrderRequest{
+		{Coin: "ETH", Sz: MustDecimal("1"), LimitPx: MustDecimal("2500"), OrderType: gtc},
+		{Coin: "ETH", Sz: MustDecimal("1.00001"), LimitPx: MustDecimal("2500.55"), OrderType: gtc},
+	}
+	err := ValidateOrderRequests(requests, meta, false)
+	var validationErr OrderValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("ValidateOrderRequests() error = %v, want OrderValidationError", err)
+	}
+	if errs := validationErr.ForOrder(0); len(errs) != 0 {
+		t.Errorf("ForOrder(0) = %v, want no errors", errs)
+	}
+	if errs := validationErr.ForOrder(1); len(errs) != 2 || errs[0].Field != "sz" || errs[1].Field != "limit_px" {
+		t.Errorf("ForOrder(1) = %v, want sz and limit_px errors", errs)
+	}
+	t.Log(err)
+}
+
+func TestExchangeAPI_BulkModifyOrdersValidatesSpot(t *testing.T) {
+	api := &ExchangeAPI{
+		meta:           map[string]AssetInfo{"ETH": {SzDecimals: 4, AssetId: 1}},
+		spotMeta:       map[string]AssetInfo{"PURR/USDC": {SzDecimals: 0, AssetId: 0}},
+		validateOrders: true,
+	}
+	gtc := OrderType{Limit: &LimitOrderType{Tif: TifGtc}}
+	requests := []ModifyOrderRequest{{OrderId: 1, Coin: "PURR/USDC", IsBuy: true, Sz: MustDecimal("1000.5"), LimitPx: MustDecimal("0.1"), OrderType: gtc}}
+	// The spot meta is used: the size has too many decimals, the coin is known
+	_, err := api.BulkModifyOrders(requests, true)
+	var validationErr OrderValidationError
+	if !errors.As(err, &validationErr) || len(validationErr) != 1 || validationErr[0].Field != "sz" {
+		t.Errorf("BulkModifyOrders(spot) error = %v, want a sz error", err)
+	}
+}
@@ -2,25 +2,34 @@ package hyperliquid
 
 import (
 	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"math/big"
+	"os"
 
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
 )
 
+// Default BIP-44 derivation path of the first Ethereum account (m/44'/60'/0'/0/0), used by MetaMask and most wallets.
+const DEFAULT_DERIVATION_PATH = "m/44'/60'/0'/0/0"
+
+// PKeyManager holds a private key in memory and implements Signer.
+// The key can be loaded from a hex string, a go-ethereum keystore file or a BIP-39 mnemonic.
+// Call Close() to wipe the key when it's no longer needed.
 type PKeyManager struct {
-	PrivateKeyStr string
-	privateKey    *ecdsa.PrivateKey
-	publicKey     *ecdsa.PublicKey
+	privateKey *ecdsa.PrivateKey
+	publicKey  *ecdsa.PublicKey
 }
 
 func (km *PKeyManager) PublicECDSA() *ecdsa.PublicKey {
 	return km.publicKey
 }
 
-func (km *PKeyManager) PrivateECDSA() *ecdsa.PrivateKey {
-	return km.privateKey
-}
-
 func (km *PKeyManager) PublicAddress() common.Address {
 	return crypto.PubkeyToAddress(*km.publicKey)
 }
@@ -29,15 +38,132 @@ func (km *PKeyManager) PublicAddressHex() string {
 	return km.PublicAddress().Hex()
 }
 
+// Address implements Signer
+func (km *PKeyManager) Address() common.Address {
+	return km.PublicAddress()
+}
+
+// SignDigest implements Signer with the in-memory private key
+func (km *PKeyManager) SignDigest(digest [32]byte) ([]byte, error) {
+	if km.privateKey == nil {
+		return nil, APIError{Message: "Private key is closed"}
+	}
+	return crypto.Sign(digest[:], km.privateKey)
+}
+
+// Close zeroes the private key. The manager can't sign anymore, but its address is kept.
+func (km *PKeyManager) Close() error {
+	if km.privateKey != nil {
+		zeroBigInt(km.privateKey.D)
+		km.privateKey = nil
+	}
+	return nil
+}
+
 // NewPKeyManager creates a new PKeyManager instance from a private key string
 func NewPKeyManager(privateKey string) (*PKeyManager, error) {
 	privKey, err := crypto.HexToECDSA(privateKey)
 	if err != nil {
 		return nil, err
 	}
-	publicKey, ok := privKey.Public().(*ecdsa.PublicKey)
-	if !ok {
+	return newPKeyManager(privKey), nil
+}
+
+// NewPKeyManagerFromKeystore creates a new PKeyManager from an encrypted go-ethereum keystore file.
+// The passphrase callback is called once to decrypt the key.
+func NewPKeyManagerFromKeystore(path string, passphrase func() (string, error)) (*PKeyManager, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewPKeyManagerFromKeystoreJSON(keyJSON, passphrase)
+}
+
+// NewPKeyManagerFromKeystoreJSON is the same as NewPKeyManagerFromKeystore but takes the content of the keystore file.
+func NewPKeyManagerFromKeystoreJSON(keyJSON []byte, passphrase func() (string, error)) (*PKeyManager, error) {
+	auth, err := passphrase()
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(keyJSON, auth)
+	if err != nil {
+		return nil, err
+	}
+	return newPKeyManager(key.PrivateKey), nil
+}
+
+// NewPKeyManagerFromMnemonic creates a new PKeyManager from a BIP-39 mnemonic and an optional passphrase.
+// The key is derived with BIP-32 along the BIP-44 path, e.g. DEFAULT_DERIVATION_PATH for the first account
+// or "m/44'/60'/0'/0/1" for the second one.
+func NewPKeyManagerFromMnemonic(mnemonic string, passphrase string, derivationPath string) (*PKeyManager, error) {
+	path, err := accounts.ParseDerivationPath(derivationPath)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(seed)
+	privKey, err := deriveKey(seed, path)
+	if err != nil {
 		return nil, err
 	}
-	return &PKeyManager{privateKey: privKey, publicKey: publicKey, PrivateKeyStr: privateKey}, nil
+	return newPKeyManager(privKey), nil
+}
+
+func newPKeyManager(privKey *ecdsa.PrivateKey) *PKeyManager {
+	return &PKeyManager{privateKey: privKey, publicKey: &privKey.PublicKey}
+}
+
+// deriveKey derives the private key of the BIP-32 path from the seed.
+// https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#private-parent-key--private-child-key
+func deriveKey(seed []byte, path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	defer clear(sum)
+	key, chainCode := sum[:32], sum[32:]
+
+	curveOrder := crypto.S256().Params().N
+	for _, index := range path {
+		var data []byte
+		if index >= 0x80000000 {
+			// Hardened child: 0x00 || parent key || index
+			data = append([]byte{0}, key...)
+		} else {
+			// Normal child: compressed parent public key || index
+			parent, err := crypto.ToECDSA(key)
+			if err != nil {
+				return nil, err
+			}
+			data = crypto.CompressPubkey(&parent.PublicKey)
+			zeroBigInt(parent.D)
+		}
+		data = binary.BigEndian.AppendUint32(data, index)
+
+		mac = hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		clear(data)
+		child := mac.Sum(nil)
+		tweak := new(big.Int).SetBytes(child[:32])
+		if tweak.Cmp(curveOrder) >= 0 {
+			zeroBigInt(tweak)
+			clear(child)
+			return nil, APIError{Message: "Invalid derived key, use another index"}
+		}
+		parentKey := new(big.Int).SetBytes(key)
+		childKey := tweak.Add(tweak, parentKey)
+		zeroBigInt(parentKey)
+		childKey.Mod(childKey, curveOrder)
+		if childKey.Sign() == 0 {
+			clear(child)
+			return nil, APIError{Message: "Invalid derived key, use another index"}
+		}
+		childKey.FillBytes(key)
+		copy(chainCode, child[32:])
+		clear(child)
+		zeroBigInt(childKey)
+	}
+	return crypto.ToECDSA(key)
 }
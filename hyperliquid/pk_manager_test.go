@@ -0,0 +1,135 @@
+package hyperliquid
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestPKeyManager_FromMnemonic(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	km, err := NewPKeyManagerFromMnemonic(mnemonic, "", DEFAULT_DERIVATION_PATH)
+	if err != nil {
+		t.Fatalf("NewPKeyManagerFromMnemonic() error = %v", err)
+	}
+	if km.PublicAddressHex() != "0x9858EfFD232B4033E47d90003D41EC34EcaEda94" {
+		t.Errorf("PublicAddressHex() = %s, want 0x9858EfFD232B4033E47d90003D41EC34EcaEda94", km.PublicAddressHex())
+	}
+	second, err := NewPKeyManagerFromMnemonic(mnemonic, "", "m/44'/60'/0'/0/1")
+	if err != nil {
+		t.Fatalf("NewPKeyManagerFromMnemonic() error = %v", err)
+	}
+	if second.PublicAddressHex() != "0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0" {
+		t.Errorf("PublicAddressHex() = %s, want 0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0", second.PublicAddressHex())
+	}
+	if _, err := NewPKeyManagerFromMnemonic("abandon abandon abandon", "", DEFAULT_DERIVATION_PATH); err == nil {
+		t.Errorf("NewPKeyManagerFromMnemonic(invalid mnemonic) error = nil, want error")
+	}
+	if _, err := NewPKeyManagerFromMnemonic(mnemonic, "", "m/44'/x"); err == nil {
+		t.Errorf("NewPKeyManagerFromMnemonic(invalid path) error = nil, want error")
+	}
+}
+
+// Test vectors 1 and 2 of BIP-32, the keys are decoded from the xprv of each path
+func TestDeriveKey(t *testing.T) {
+	tests := []struct {
+		seed string
+		path string
+		want string
+	}{
+		{"000102030405060708090a0b0c0d0e0f", "m/0'", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{"000102030405060708090a0b0c0d0e0f", "m/0'/1", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+		{"000102030405060708090a0b0c0d0e0f", "m/0'/1/2'", "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca"},
+		{"000102030405060708090a0b0c0d0e0f", "m/0'/1/2'/2", "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4"},
+		{"000102030405060708090a0b0c0d0e0f", "m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+		{
+			"fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542",
+			"m/0", "abe74a98f6c7eabee0428f53798f0ab8aa1bd37873999041703c742f15ac7e1e",
+		},
+		{
+			"fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542",
+			"m/0/2147483647'", "877c779ad9687164e9c2f4f0f4ff0340814392330693ce95a58fe18fd52e6e93",
+		},
+		{
+			"fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542",
+			"m/0/2147483647'/1", "704addf544a06e5ee4bea37098463c23613da32020d604506da8c0518e1da4b7",
+		},
+		{
+			"fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542",
+			"m/0/2147483647'/1/2147483646'", "f1c7c871a54a804afe328b4c83a1c33b8e5ff48f5087273f04efa83b247d6a2d",
+		},
+		{
+			"fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542",
+			"m/0/2147483647'/1/2147483646'/2", "bb7d39bdb83ecf58f2fd82b6d918341cbef428661ef01ab97c28a4842125ac23",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			path, err := accounts.ParseDerivationPath(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			key, err := deriveKey(common.FromHex(tt.seed), path)
+			if err != nil {
+				t.Fatalf("deriveKey() error = %v", err)
+			}
+			if got := hex.EncodeToString(crypto.FromECDSA(key)); got != tt.want {
+				t.Errorf("deriveKey() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPKeyManager_FromKeystore(t *testing.T) {
+	km, _ := NewPKeyManager(testPrivateKey)
+	keyJSON, err := keystore.EncryptKey(&keystore.Key{
+		Address:    km.PublicAddress(),
+		PrivateKey: km.privateKey,
+	}, "secret", keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "key.json")
+	if err := os.WriteFile(path, keyJSON, 0600); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := NewPKeyManagerFromKeystore(path, func() (string, error) { return "secret", nil })
+	if err != nil {
+		t.Fatalf("NewPKeyManagerFromKeystore() error = %v", err)
+	}
+	if loaded.PublicAddress() != km.PublicAddress() {
+		t.Errorf("PublicAddress() = %s, want %s", loaded.PublicAddress(), km.PublicAddress())
+	}
+	if _, err := NewPKeyManagerFromKeystore(path, func() (string, error) { return "wrong", nil }); err == nil {
+		t.Errorf("NewPKeyManagerFromKeystore(wrong passphrase) error = nil, want error")
+	}
+}
+
+func TestPKeyManager_Close(t *testing.T) {
+	km, _ := NewPKeyManager(testPrivateKey)
+	words := km.privateKey.D.Bits()
+	if err := km.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	// Every word of the key must be wiped in memory, not only the length of the number
+	for i, word := range words {
+		if word != 0 {
+			t.Errorf("word %d of the private key = %x after Close(), want 0", i, word)
+		}
+	}
+	if km.privateKey != nil {
+		t.Errorf("privateKey after Close() = %v, want nil", km.privateKey)
+	}
+	if _, err := km.SignDigest([32]byte{}); err == nil {
+		t.Errorf("SignDigest() after Close() error = nil, want error")
+	}
+	if km.PublicAddressHex() == "" {
+		t.Errorf("PublicAddressHex() after Close() is empty")
+	}
+}
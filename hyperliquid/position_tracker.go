@@ -0,0 +1,331 @@
+package hyperliquid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TrackedPosition is a snapshot of a perpetual position tracked by PositionTracker
+type TrackedPosition struct {
+	Coin             string
+	Szi              Decimal // Signed size, negative for a short position
+	EntryPx          Decimal // Average entry price
+	MarkPx           Decimal // Last mid price, or the mark price of the last resync
+	FundingSinceOpen Decimal // Funding received (positive) or paid (negative) since the position was opened
+	LiquidationPx    Decimal // Liquidation price of the last resync, zero if there is none
+	SyncedAt         int64   // Time of the last resync in milliseconds
+	UpdatedAt        int64   // Time of the last fill or funding in milliseconds
+}
+
+// UnrealizedPnl returns the PnL of the position at the mark price
+func (position TrackedPosition) UnrealizedPnl() Decimal {
+	return position.MarkPx.Sub(position.EntryPx).Mul(position.Szi)
+}
+
+// LiquidationDistance returns the distance from the mark price to the liquidation price relative to the mark price,
+// e.g. 0.25 if the price can move 25% before the position is liquidated.
+// It returns false if the position has no liquidation price.
+func (position TrackedPosition) LiquidationDistance() (Decimal, bool) {
+	if position.LiquidationPx.IsZero() || position.MarkPx.IsZero() {
+		return Decimal{}, false
+	}
+	return position.MarkPx.Sub(position.LiquidationPx).Abs().Div(position.MarkPx), true
+}
+
+// PositionTracker keeps the perpetual positions of a user up to date from the fills and the fundings,
+// and marks them to the live mid prices.
+//
+// Like OrderManager it has no connection of its own: feed it the messages of the userFills,
+// userFundings and allMids websocket channels with HandleMessage.
+// Each fill is checked against the position it started from (and each funding against the position it was paid on).
+// If they don't match, a message was missed and the positions are resynced from clearinghouseState (see GetUserState).
+// The liquidation prices only change on resync, run ResyncEvery to refresh them.
+//
+//	tracker := NewPositionTracker(infoAPI, address)
+//	if err := tracker.Resync(); err != nil { ... }
+//	go tracker.ResyncEvery(ctx, time.Minute)
+//	// for each websocket message: tracker.HandleMessage(message)
+//	position, ok := tracker.Position("ETH")
+type PositionTracker struct {
+	mu          sync.Mutex
+	info        *InfoAPI
+	address     string
+	positions   map[string]*TrackedPosition
+	mids        map[string]Decimal
+	synced      bool
+	syncTime    int64            // Time of the last clearinghouseState, older events are part of it
+	fills       map[int64]bool   // Trade ids of the fills applied since the last resync
+	lastFunding map[string]int64 // Time of the last funding applied per coin
+	resyncs     int              // Number of resyncs fetching clearinghouseState
+	replay      []func() bool    // Events handled during the fetches, applied again over the new state
+}
+
+// NewPositionTracker creates a tracker of the positions of address.
+// The positions are empty until the first Resync, which is also done by the first message that needs it.
+func NewPositionTracker(info *InfoAPI, address string) *PositionTracker {
+	return &PositionTracker{
+		info:        info,
+		address:     address,
+		positions:   make(map[string]*TrackedPosition),
+		mids:        make(map[string]Decimal),
+		fills:       make(map[int64]bool),
+		lastFunding: make(map[string]int64),
+	}
+}
+
+// Resync replaces the positions with the clearinghouseState of the user.
+// The fills and fundings handled while the state is fetched are applied again over it if they are newer.
+func (pt *PositionTracker) Resync() error {
+	pt.mu.Lock()
+	pt.resyncs++
+	pt.mu.Unlock()
+	state, err := pt.info.GetUserState(pt.address)
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	pt.resyncs--
+	replay := pt.replay
+	if pt.resyncs == 0 {
+		pt.replay = nil
+	}
+	if err != nil {
+		return err
+	}
+	clear(pt.positions)
+	for _, assetPosition := range state.AssetPositions {
+		position := assetPosition.Position
+		if position.Szi.IsZero() {
+			continue
+		}
+		markPx, ok := pt.mids[position.Coin]
+		if !ok {
+			markPx = position.PositionValue.Div(position.Szi.Abs())
+		}
+		pt.positions[position.Coin] = &TrackedPosition{
+			Coin:    position.Coin,
+			Szi:     position.Szi,
+			EntryPx: position.EntryPx,
+			MarkPx:  markPx,
+			// cumFunding counts the funding paid
+			FundingSinceOpen: position.CumFunding.SinceOpne.Neg(),
+			LiquidationPx:    position.LiquidationPx,
+			SyncedAt:         state.Time,
+			UpdatedAt:        state.Time,
+		}
+	}
+	pt.synced = true
+	pt.syncTime = state.Time
+	clear(pt.fills)
+	clear(pt.lastFunding)
+	for _, apply := range replay {
+		if !apply() {
+			// The events contradict the state, the next one resyncs again
+			pt.synced = false
+			break
+		}
+	}
+	return nil
+}
+
+// ResyncEvery runs Resync every interval until ctx is done.
+// Errors are logged in debug mode and retried at the next interval.
+func (pt *PositionTracker) ResyncEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := pt.Resync(); err != nil {
+				pt.info.debug("Error resyncing positions: %s", err)
+			}
+		}
+	}
+}
+
+// Helper function to apply n events in order, apply returns false if the event shows a gap.
+// The positions are then resynced, which covers the event with the gap.
+// The events handled while a resync is fetching the state are kept to be applied again over it.
+func (pt *PositionTracker) applyAll(n int, apply func(i int) bool) error {
+	for i := 0; i < n; i++ {
+		pt.mu.Lock()
+		for ; i < n; i++ {
+			if pt.resyncs > 0 {
+				event := i
+				pt.replay = append(pt.replay, func() bool { return apply(event) })
+			}
+			if !apply(i) {
+				break
+			}
+		}
+		pt.mu.Unlock()
+		if i == n {
+			return nil
+		}
+		pt.info.debug("Position gap detected, resyncing positions of %s", pt.address)
+		if err := pt.Resync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HandleFills applies the fills of a userFills websocket message. Spot fills are ignored.
+// It returns an error if a gap was detected and the resync failed.
+func (pt *PositionTracker) HandleFills(fills []OrderFill) error {
+	return pt.applyAll(len(fills), func(i int) bool {
+		return pt.applyFill(fills[i])
+	})
+}
+
+// Helper function to apply a fill, it returns false if the fill doesn't start from the tracked position
+func (pt *PositionTracker) applyFill(fill OrderFill) bool {
+	if isSpotCoin(fill.Coin) {
+		return true
+	}
+	if !pt.synced {
+		return false
+	}
+	if pt.fills[fill.Tid] || fill.Time <= pt.syncTime {
+		return true
+	}
+	position, ok := pt.positions[fill.Coin]
+	if !ok {
+		position = &TrackedPosition{Coin: fill.Coin, MarkPx: pt.mids[fill.Coin]}
+	}
+	if !fill.StartPosition.Equal(position.Szi) {
+		return false
+	}
+	pt.fills[fill.Tid] = true
+
+	delta := fill.Sz
+	if fill.Side != "B" {
+		delta = delta.Neg()
+	}
+	szi := position.Szi.Add(delta)
+	switch {
+	case szi.IsZero():
+		delete(pt.positions, fill.Coin)
+		return true
+	case position.Szi.IsZero() || position.Szi.Sign() == delta.Sign():
+		// Opened or increased: average the entry price
+		entryNtl := position.EntryPx.Mul(position.Szi.Abs()).Add(fill.Px.Mul(fill.Sz))
+		position.EntryPx = entryNtl.Div(szi.Abs())
+	case position.Szi.Sign() != szi.Sign():
+		// Flipped: a new position is opened at the fill price
+		position.EntryPx = fill.Px
+		position.FundingSinceOpen = Decimal{}
+		position.LiquidationPx = Decimal{}
+	}
+	position.Szi = szi
+	position.UpdatedAt = fill.Time
+	pt.positions[fill.Coin] = position
+	return true
+}
+
+// HandleFundings applies the funding payments of a userFundings websocket message.
+// It returns an error if a gap was detected and the resync failed.
+func (pt *PositionTracker) HandleFundings(fundings []WsFunding) error {
+	return pt.applyAll(len(fundings), func(i int) bool {
+		return pt.applyFunding(fundings[i])
+	})
+}
+
+// Helper function to apply a funding payment, it returns false if it was paid on another size than the tracked one
+func (pt *PositionTracker) applyFunding(funding WsFunding) bool {
+	if !pt.synced {
+		return false
+	}
+	if funding.Time <= pt.syncTime || funding.Time <= pt.lastFunding[funding.Coin] {
+		return true
+	}
+	position, ok := pt.positions[funding.Coin]
+	if !ok || !funding.Szi.Equal(position.Szi) {
+		return false
+	}
+	pt.lastFunding[funding.Coin] = funding.Time
+	position.FundingSinceOpen = position.FundingSinceOpen.Add(funding.Usdc)
+	position.UpdatedAt = funding.Time
+	return true
+}
+
+// HandleMids marks the positions to the mid prices of an allMids websocket message
+func (pt *PositionTracker) HandleMids(mids map[string]string) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	for coin, mid := range mids {
+		px, err := NewDecimalFromString(mid)
+		if err != nil {
+			continue
+		}
+		pt.mids[coin] = px
+		if position, ok := pt.positions[coin]; ok {
+			position.MarkPx = px
+		}
+	}
+}
+
+// HandleMessage decodes a websocket message and applies it if it's from the userFills, userFundings or allMids channel.
+// Messages of other channels are ignored.
+func (pt *PositionTracker) HandleMessage(data []byte) error {
+	var message WsMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return err
+	}
+	switch message.Channel {
+	case "userFills":
+		var fills WsUserFills
+		if err := json.Unmarshal(message.Data, &fills); err != nil {
+			return fmt.Errorf("invalid userFills message: %w", err)
+		}
+		return pt.HandleFills(fills.Fills)
+	case "userFundings":
+		var fundings WsUserFundings
+		if err := json.Unmarshal(message.Data, &fundings); err != nil {
+			return fmt.Errorf("invalid userFundings message: %w", err)
+		}
+		return pt.HandleFundings(fundings.Fundings)
+	case "allMids":
+		var mids WsAllMids
+		if err := json.Unmarshal(message.Data, &mids); err != nil {
+			return fmt.Errorf("invalid allMids message: %w", err)
+		}
+		pt.HandleMids(mids.Mids)
+	}
+	return nil
+}
+
+// Position returns the position of the coin, false if there is none
+func (pt *PositionTracker) Position(coin string) (TrackedPosition, bool) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	position, ok := pt.positions[coin]
+	if !ok {
+		return TrackedPosition{}, false
+	}
+	return *position, true
+}
+
+// Positions returns all the open positions sorted by coin
+func (pt *PositionTracker) Positions() []TrackedPosition {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	positions := make([]TrackedPosition, 0, len(pt.positions))
+	for _, position := range pt.positions {
+		positions = append(positions, *position)
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].Coin < positions[j].Coin
+	})
+	return positions
+}
+
+// Helper function to tell spot coins ("@107", "PURR/USDC") from perpetuals
+func isSpotCoin(coin string) bool {
+	return strings.HasPrefix(coin, "@") || strings.Contains(coin, "/")
+}
@@ -0,0 +1,122 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPositionTracker(t *testing.T) {
+	states := []string{
+		`{"assetPositions":[{"type":"oneWay","position":{"coin":"ETH","szi":"1","entryPx":"2000","liquidationPx":"1500","positionValue":"2100","cumFunding":{"allTime":"9","sinceOpen":"5","sinceChange":"1"}}}],"time":1000}`,
+		`{"assetPositions":[{"type":"oneWay","position":{"coin":"BTC","szi":"-0.5","entryPx":"60000","liquidationPx":null,"positionValue":"30500","cumFunding":{"allTime":"0","sinceOpen":"-2","sinceChange":"0"}}}],"time":9000}`,
+	}
+	resyncs := 0
+	info := GetMockInfoAPI(t, func(request UserStateRequest) any {
+		if request.Typez != "clearinghouseState" || request.User != "0x1" {
+			t.Errorf("request = %+v, want clearinghouseState of 0x1", request)
+		}
+		resyncs++
+		return json.RawMessage(states[min(resyncs, len(states))-1])
+	})
+	tracker := NewPositionTracker(info, "0x1")
+
+	// The first fill needs a resync, the snapshot fills are part of clearinghouseState
+	messages := []string{
+		`{"channel":"allMids","data":{"mids":{"ETH":"2200","BTC":"61000"}}}`,
+		`{"channel":"userFills","data":{"isSnapshot":true,"user":"0x1","fills":[{"coin":"ETH","px":"2000","sz":"1","side":"B","startPosition":"0","tid":1,"time":900}]}}`,
+		`{"channel":"userFills","data":{"user":"0x1","fills":[{"coin":"@107","px":"20","sz":"5","side":"B","startPosition":"0","tid":2,"time":1500}]}}`,
+	}
+	for _, message := range messages {
+		if err := tracker.HandleMessage([]byte(message)); err != nil {
+			t.Fatalf("HandleMessage() error = %v", err)
+		}
+	}
+	position, ok := tracker.Position("ETH")
+	if !ok || resyncs != 1 {
+		t.Fatalf("Position(ETH) = %+v, %v after %d resyncs, want a position after 1 resync", position, ok, resyncs)
+	}
+	if !position.MarkPx.Equal(MustDecimal("2200")) || !position.UnrealizedPnl().Equal(MustDecimal("200")) {
+		t.Errorf("Position(ETH) mark = %s PnL = %s, want 2200 and 200", position.MarkPx, position.UnrealizedPnl())
+	}
+	if !position.FundingSinceOpen.Equal(MustDecimal("-5")) {
+		t.Errorf("Position(ETH).FundingSinceOpen = %s, want -5", position.FundingSinceOpen)
+	}
+	if distance, ok := position.LiquidationDistance(); !ok || distance.StringFixed(4) != "0.3182" {
+		t.Errorf("LiquidationDistance() = %s, %v, want 0.3182", distance, ok)
+	}
+
+	fills := []OrderFill{
+		{Coin: "ETH", Px: MustDecimal("2400"), Sz: MustDecimal("1"), Side: "B", StartPosition: MustDecimal("1"), Tid: 3, Time: 2000},
+		{Coin: "ETH", Px: MustDecimal("2400"), Sz: MustDecimal("1"), Side: "B", StartPosition: MustDecimal("1"), Tid: 3, Time: 2000},
+	}
+	if err := tracker.HandleFills(fills); err != nil {
+		t.Fatalf("HandleFills() error = %v", err)
+	}
+	fundings := []WsFunding{
+		{Coin: "ETH", Usdc: MustDecimal("-1.5"), Szi: MustDecimal("2"), Time: 3000},
+		{Coin: "ETH", Usdc: MustDecimal("-1.5"), Szi: MustDecimal("2"), Time: 3000},
+	}
+	if err := tracker.HandleFundings(fundings); err != nil {
+		t.Fatalf("HandleFundings() error = %v", err)
+	}
+	position, _ = tracker.Position("ETH")
+	if !position.Szi.Equal(MustDecimal("2")) || !position.EntryPx.Equal(MustDecimal("2200")) || !position.FundingSinceOpen.Equal(MustDecimal("-6.5")) {
+		t.Errorf("Position(ETH) = %s at %s funding %s, want 2 at 2200 funding -6.5", position.Szi, position.EntryPx, position.FundingSinceOpen)
+	}
+
+	// Flipping to short opens a new position
+	tracker.HandleFills([]OrderFill{{Coin: "ETH", Px: MustDecimal("2300"), Sz: MustDecimal("3"), Side: "A", StartPosition: MustDecimal("2"), Tid: 4, Time: 4000}})
+	position, _ = tracker.Position("ETH")
+	if !position.Szi.Equal(MustDecimal("-1")) || !position.EntryPx.Equal(MustDecimal("2300")) || !position.FundingSinceOpen.IsZero() {
+		t.Errorf("Position(ETH) = %s at %s funding %s, want -1 at 2300 funding 0", position.Szi, position.EntryPx, position.FundingSinceOpen)
+	}
+	if _, ok := position.LiquidationDistance(); ok {
+		t.Errorf("LiquidationDistance() ok = true after a flip, want false until resync")
+	}
+
+	// A fill that doesn't start from the tracked position shows a missed message
+	if err := tracker.HandleFills([]OrderFill{{Coin: "ETH", Px: MustDecimal("2300"), Sz: MustDecimal("1"), Side: "B", StartPosition: MustDecimal("-3"), Tid: 6, Time: 5000}}); err != nil {
+		t.Fatalf("HandleFills() error = %v", err)
+	}
+	positions := tracker.Positions()
+	if resyncs != 2 || len(positions) != 1 || positions[0].Coin != "BTC" {
+		t.Fatalf("Positions() = %+v after %d resyncs, want BTC after 2 resyncs", positions, resyncs)
+	}
+	if !positions[0].MarkPx.Equal(MustDecimal("61000")) || !positions[0].UnrealizedPnl().Equal(MustDecimal("-500")) || !positions[0].FundingSinceOpen.Equal(MustDecimal("2")) {
+		t.Errorf("Position(BTC) = %+v, want marked at 61000 with -500 PnL and 2 funding", positions[0])
+	}
+	if _, ok := positions[0].LiquidationDistance(); ok {
+		t.Errorf("LiquidationDistance() ok = true without liquidation price")
+	}
+}
+
+func TestPositionTracker_FillsDuringResync(t *testing.T) {
+	var tracker *PositionTracker
+	resyncs := 0
+	info := GetMockInfoAPI(t, func(request UserStateRequest) any {
+		resyncs++
+		if resyncs == 1 {
+			return json.RawMessage(`{"assetPositions":[{"type":"oneWay","position":{"coin":"ETH","szi":"1","entryPx":"2000","positionValue":"2000","cumFunding":{"allTime":"0","sinceOpen":"0","sinceChange":"0"}}}],"time":1000}`)
+		}
+		// Fills arrive while the state is fetched: the first one is part of it, the second one is newer
+		tracker.HandleFills([]OrderFill{
+			{Coin: "ETH", Px: MustDecimal("2000"), Sz: MustDecimal("1"), Side: "B", StartPosition: MustDecimal("1"), Tid: 7, Time: 1500},
+			{Coin: "ETH", Px: MustDecimal("2300"), Sz: MustDecimal("1"), Side: "B", StartPosition: MustDecimal("2"), Tid: 8, Time: 2500},
+		})
+		return json.RawMessage(`{"assetPositions":[{"type":"oneWay","position":{"coin":"ETH","szi":"2","entryPx":"2000","positionValue":"4000","cumFunding":{"allTime":"0","sinceOpen":"0","sinceChange":"0"}}}],"time":2000}`)
+	})
+	tracker = NewPositionTracker(info, "0x1")
+	if err := tracker.Resync(); err != nil {
+		t.Fatalf("Resync() error = %v", err)
+	}
+	if err := tracker.Resync(); err != nil {
+		t.Fatalf("Resync() error = %v", err)
+	}
+	position, _ := tracker.Position("ETH")
+	if resyncs != 2 || !position.Szi.Equal(MustDecimal("3")) || !position.EntryPx.Equal(MustDecimal("2100")) {
+		t.Errorf("Position(ETH) = %s at %s after %d resyncs, want 3 at 2100 after 2", position.Szi, position.EntryPx, resyncs)
+	}
+	if len(tracker.replay) != 0 {
+		t.Errorf("replay = %d events, want none after the resync", len(tracker.replay))
+	}
+}
@@ -3,26 +3,30 @@ package hyperliquid
 import (
 	"encoding/binary"
 	"fmt"
-	"log"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
-	"github.com/vmihailenco/msgpack/v5"
 )
 
 // SignRequest is the implementation of EIP-712 typed data
 type SignRequest struct {
-	PrimaryType string
-	DType       []apitypes.Type
-	DTypeMsg    map[string]interface{}
-	IsMainNet   bool
-	DomainName  string
+	PrimaryType      string
+	DType            []apitypes.Type
+	DTypeMsg         map[string]interface{}
+	IsMainNet        bool
+	DomainName       string
+	SignatureChainId string // Chain id of user signed actions (e.g. "0x66eee"), defaults to Arbitrum
 }
 
 func (request *SignRequest) getChainId() *math.HexOrDecimal256 {
 	if request.DomainName == "HyperliquidSignTransaction" {
+		var chainId math.HexOrDecimal256
+		if request.SignatureChainId != "" && chainId.UnmarshalText([]byte(request.SignatureChainId)) == nil {
+			return &chainId
+		}
 		if request.IsMainNet {
 			return math.NewHexOrDecimal256(int64(ARBITRUM_CHAIN_ID))
 		}
@@ -53,36 +57,6 @@ func (request *SignRequest) GetDomain() apitypes.TypedDataDomain {
 	}
 }
 
-type Signer struct {
-	manager *PKeyManager
-}
-
-func NewSigner(manager *PKeyManager) Signer {
-	return Signer{
-		manager: manager,
-	}
-}
-
-func (signer *Signer) Sign(request *SignRequest) (byte, [32]byte, [32]byte, error) {
-	return signer.signInternal(SignRequestToEIP712TypedData(request))
-}
-
-// signInternal signs the typed data and returns the signature in VRS format
-func (signer *Signer) signInternal(message apitypes.TypedData) (byte, [32]byte, [32]byte, error) {
-	pkey := signer.manager.PrivateECDSA()
-	bytes, _, err := apitypes.TypedDataAndHash(message)
-	if err != nil {
-		log.Printf("Error hashing typed data: %s", err)
-		return 0, [32]byte{}, [32]byte{}, err
-	}
-	signature, err := crypto.Sign(bytes, pkey)
-	if err != nil {
-		log.Printf("Error signing typed data: %s", err)
-		return 0, [32]byte{}, [32]byte{}, err
-	}
-	return SignatureToVRS(signature)
-}
-
 func SignRequestToEIP712TypedData(request *SignRequest) apitypes.TypedData {
 	return apitypes.TypedData{
 		Domain:      request.GetDomain(),
@@ -92,6 +66,17 @@ func SignRequestToEIP712TypedData(request *SignRequest) apitypes.TypedData {
 	}
 }
 
+// Digest returns the EIP-712 hash of the typed data, which is the digest signed by the wallet.
+func (request *SignRequest) Digest() ([32]byte, error) {
+	var digest [32]byte
+	hash, _, err := apitypes.TypedDataAndHash(SignRequestToEIP712TypedData(request))
+	if err != nil {
+		return digest, fmt.Errorf("error hashing typed data: %w", err)
+	}
+	copy(digest[:], hash)
+	return digest, nil
+}
+
 func SignatureToVRS(sig []byte) (byte, [32]byte, [32]byte, error) {
 	var v byte
 	var r [32]byte
@@ -104,10 +89,15 @@ func SignatureToVRS(sig []byte) (byte, [32]byte, [32]byte, error) {
 
 // Create a hash of an action (json object)
 func buildActionHash(action any, vaultAd string, nonce uint64) (common.Hash, error) {
-	data, err := msgpack.Marshal(action)
+	data, err := EncodeAction(action)
 	if err != nil {
 		return common.Hash{}, fmt.Errorf("error while marshaling action: %s", err)
 	}
+	return buildActionHashFromMsgpack(data, vaultAd, nonce), nil
+}
+
+// Create a hash of an action already encoded with msgpack
+func buildActionHashFromMsgpack(data []byte, vaultAd string, nonce uint64) common.Hash {
 	nonceBytes := make([]byte, 8)
 	binary.BigEndian.PutUint64(nonceBytes, uint64(nonce))
 	data = ArrayAppend(data, nonceBytes)
@@ -118,8 +108,7 @@ func buildActionHash(action any, vaultAd string, nonce uint64) (common.Hash, err
 		data = ArrayAppend(data, []byte("\x01"))
 		data = ArrayAppend(data, HexToBytes(vaultAd))
 	}
-	result := crypto.Keccak256Hash(data)
-	return result, nil
+	return crypto.Keccak256Hash(data)
 }
 
 func getNetSource(isMainnet bool) string {
@@ -135,6 +124,6 @@ func buildMessage(hash []byte, isMainnet bool) apitypes.TypedDataMessage {
 	source := getNetSource(isMainnet)
 	return apitypes.TypedDataMessage{
 		"source":       source,
-		"connectionId": hash,
+		"connectionId": hexutil.Bytes(hash), // Hex in the JSON of eth_signTypedData_v4
 	}
 }
@@ -0,0 +1,176 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// ErrNetworkMismatch is returned when a request was signed for the other network (mainnet vs testnet).
+var ErrNetworkMismatch = errors.New("request is signed for another network")
+
+// ErrSignerMismatch is returned by VerifySigner and SubmitSigned when the request is signed by another address.
+var ErrSignerMismatch = errors.New("request is signed by another address")
+
+// RecoverSigner returns the address that signed the exchange request.
+//
+// It rebuilds the EIP-712 digest from the action, nonce and vault address:
+// user signed actions (withdraw3, usdSend, spotSend...) are recognised by their signatureChainId field,
+// multiSig actions are signed by their outer signer and all other actions are L1 actions signed by an agent.
+// User signed actions for the wrong network return ErrNetworkMismatch.
+// L1 actions don't contain the network, so a signature for the other network recovers to a different address
+// (see VerifySigner to detect it).
+//
+// The action must be a struct of this package (e.g. PlaceOrderAction) or raw JSON.
+// Requests decoded with json.Unmarshal keep the action as json.RawMessage, so the key order
+// that the L1 hash depends on is preserved.
+func RecoverSigner(req ExchangeRequest, isMainnet bool) (common.Address, error) {
+	signRequest, err := buildRequestSignRequest(req, isMainnet)
+	if err != nil {
+		return common.Address{}, err
+	}
+	hash, _, err := apitypes.TypedDataAndHash(SignRequestToEIP712TypedData(signRequest))
+	if err != nil {
+		return common.Address{}, fmt.Errorf("error hashing typed data: %w", err)
+	}
+	r := common.FromHex(req.Signature.R)
+	s := common.FromHex(req.Signature.S)
+	if len(r) > 32 || len(s) > 32 {
+		return common.Address{}, APIError{Message: "Invalid signature"}
+	}
+	v := req.Signature.V
+	if v >= 27 {
+		v -= 27
+	}
+	signature := make([]byte, crypto.SignatureLength)
+	copy(signature[32-len(r):32], r)
+	copy(signature[64-len(s):64], s)
+	signature[64] = v
+	publicKey, err := crypto.SigToPub(hash, signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid signature: %w", err)
+	}
+	return crypto.PubkeyToAddress(*publicKey), nil
+}
+
+// VerifySigner checks that the exchange request is signed by the expected address for the given network.
+// It returns ErrNetworkMismatch if the request is signed by the expected address for the other network
+// and ErrSignerMismatch if it is signed by another address.
+func VerifySigner(req ExchangeRequest, isMainnet bool, expected common.Address) error {
+	signer, err := RecoverSigner(req, isMainnet)
+	if err != nil {
+		return err
+	}
+	if signer == expected {
+		return nil
+	}
+	if otherNet, err := RecoverSigner(req, !isMainnet); err == nil && otherNet == expected {
+		return fmt.Errorf("%w: expected %s", ErrNetworkMismatch, getNetworkName(isMainnet))
+	}
+	return fmt.Errorf("%w: %s, expected %s", ErrSignerMismatch, signer, expected)
+}
+
+func getNetworkName(isMainnet bool) string {
+	if isMainnet {
+		return "Mainnet"
+	}
+	return "Testnet"
+}
+
+// Rebuilds the EIP-712 request that was signed for the exchange request
+func buildRequestSignRequest(req ExchangeRequest, isMainnet bool) (*SignRequest, error) {
+	var fields map[string]any
+	switch action := req.Action.(type) {
+	case json.RawMessage:
+		var value any
+		if err := json.Unmarshal(action, &value); err != nil {
+			return nil, fmt.Errorf("invalid action: %w", err)
+		}
+		// Arrays are the multi-sig payloads of L1 actions (see MultiSigCollector)
+		fields, _ = value.(map[string]any)
+	case map[string]any:
+		fields = action
+	default:
+		var err error
+		if fields, err = StructToMap(action); err != nil {
+			return nil, err
+		}
+	}
+	actionType, _ := fields["type"].(string)
+	vaultAddress := ""
+	if req.VaultAddress != nil {
+		vaultAddress = *req.VaultAddress
+	}
+
+	// Multi-sig action signed by the outer signer
+	if actionType == "multiSig" {
+		signatureChainId, _ := fields["signatureChainId"].(string)
+		hash, err := multiSigActionHash(req.Action, vaultAddress, req.Nonce)
+		if err != nil {
+			return nil, fmt.Errorf("error while marshaling action: %w", err)
+		}
+		return buildMultiSigSignRequest(hash, signatureChainId, req.Nonce, isMainnet), nil
+	}
+
+	// User signed action
+	if signatureChainId, ok := fields["signatureChainId"].(string); ok {
+		userSigned, ok := userSignedActionTypes[actionType]
+		if !ok {
+			return nil, APIError{Message: fmt.Sprintf("Unknown user signed action: %s", actionType)}
+		}
+		// Signature of an authorized user of a multi-sig account (see MultiSigCollector)
+		if _, ok := fields["payloadMultiSigUser"]; ok {
+			userSigned = userSigned.forMultiSig()
+		}
+		if chain, _ := fields["hyperliquidChain"].(string); chain != getNetworkName(isMainnet) {
+			return nil, fmt.Errorf("%w: action is for %q, expected %s", ErrNetworkMismatch, chain, getNetworkName(isMainnet))
+		}
+		// The exchange only accepts the nonce signed in the action
+		for _, field := range userSigned.Types {
+			if field.Name != "time" && field.Name != "nonce" {
+				continue
+			}
+			if nonce, _ := fields[field.Name].(float64); nonce != float64(req.Nonce) {
+				return nil, APIError{Message: fmt.Sprintf("Action %s %v doesn't match the nonce %d", field.Name, fields[field.Name], req.Nonce)}
+			}
+		}
+		message := make(map[string]any, len(fields))
+		for key, value := range fields {
+			message[key] = value
+		}
+		delete(message, "type")
+		delete(message, "signatureChainId")
+		return &SignRequest{
+			DomainName:       "HyperliquidSignTransaction",
+			PrimaryType:      userSigned.PrimaryType,
+			DType:            userSigned.Types,
+			DTypeMsg:         message,
+			IsMainNet:        isMainnet,
+			SignatureChainId: signatureChainId,
+		}, nil
+	}
+
+	// L1 action signed by an agent
+	if _, ok := req.Action.(map[string]any); ok {
+		return nil, APIError{Message: "L1 action must be a struct or json.RawMessage to keep its key order"}
+	}
+	data, err := EncodeAction(req.Action)
+	if err != nil {
+		return nil, fmt.Errorf("error while marshaling action: %w", err)
+	}
+	hash := buildActionHashFromMsgpack(data, vaultAddress, req.Nonce)
+	return &SignRequest{
+		DomainName:  "Exchange",
+		PrimaryType: "Agent",
+		DType: []apitypes.Type{
+			{Name: "source", Type: "string"},
+			{Name: "connectionId", Type: "bytes32"},
+		},
+		DTypeMsg:  buildMessage(hash.Bytes(), isMainnet),
+		IsMainNet: isMainnet,
+	}, nil
+}
@@ -0,0 +1,124 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func getTestSigningExchangeAPI(t *testing.T, isMainnet bool) *ExchangeAPI {
+	api := &ExchangeAPI{
+		Client:       *NewClient(isMainnet),
+		baseEndpoint: "/exchange",
+	}
+	if err := api.SetPrivateKey(testPrivateKey); err != nil {
+		t.Fatal(err)
+	}
+	return api
+}
+
+func TestRecoverSigner_L1Action(t *testing.T) {
+	api := getTestSigningExchangeAPI(t, false)
+	meta := map[string]AssetInfo{"ETH": {SzDecimals: 4, AssetId: 1}}
+	orderType := OrderType{Limit: &LimitOrderType{Tif: TifGtc}}
+	wire := OrderRequestToWire(NewOrderRequest("ETH", 0.1, 2500.5, orderType, false), meta, false)
+	action := OrderWiresToOrderAction([]OrderWire{wire}, GroupingNa)
+	nonce := uint64(1700000000000)
+	v, r, s, err := api.SignL1Action(action, nonce)
+	if err != nil {
+		t.Fatalf("SignL1Action() error = %v", err)
+	}
+	request := ExchangeRequest{Action: action, Nonce: nonce, Signature: ToTypedSig(r, s, v)}
+
+	signer, err := RecoverSigner(request, false)
+	if err != nil {
+		t.Fatalf("RecoverSigner() error = %v", err)
+	}
+	if signer != api.Signer().Address() {
+		t.Errorf("RecoverSigner() = %s, want %s", signer, api.Signer().Address())
+	}
+
+	// The gateway receives the request as JSON
+	data, _ := json.Marshal(request)
+	var decoded ExchangeRequest
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := decoded.Action.(json.RawMessage); !ok {
+		t.Fatalf("decoded action is %T, want json.RawMessage", decoded.Action)
+	}
+	signer, err = RecoverSigner(decoded, false)
+	if err != nil {
+		t.Fatalf("RecoverSigner(decoded) error = %v", err)
+	}
+	if signer != api.Signer().Address() {
+		t.Errorf("RecoverSigner(decoded) = %s, want %s", signer, api.Signer().Address())
+	}
+
+	if err := VerifySigner(decoded, false, api.Signer().Address()); err != nil {
+		t.Errorf("VerifySigner() error = %v", err)
+	}
+	if err := VerifySigner(decoded, true, api.Signer().Address()); !errors.Is(err, ErrNetworkMismatch) {
+		t.Errorf("VerifySigner(mainnet) error = %v, want ErrNetworkMismatch", err)
+	}
+	vault := "0x0000000000000000000000000000000000000001"
+	decoded.VaultAddress = &vault
+	if err := VerifySigner(decoded, false, api.Signer().Address()); !errors.Is(err, ErrSignerMismatch) {
+		t.Errorf("VerifySigner(vault) error = %v, want ErrSignerMismatch", err)
+	}
+}
+
+func TestRecoverSigner_UserSignedAction(t *testing.T) {
+	api := getTestSigningExchangeAPI(t, false)
+	signatureChainId, chain := api.getChainParams()
+	action := WithdrawAction{
+		Type:             "withdraw3",
+		Destination:      "0x0000000000000000000000000000000000000002",
+		Amount:           "10.5",
+		Time:             1700000000000,
+		HyperliquidChain: chain,
+		SignatureChainID: signatureChainId,
+	}
+	v, r, s, err := api.SignWithdrawAction(action)
+	if err != nil {
+		t.Fatalf("SignWithdrawAction() error = %v", err)
+	}
+	request := ExchangeRequest{Action: action, Nonce: action.Time, Signature: ToTypedSig(r, s, v)}
+	data, _ := json.Marshal(request)
+	var decoded ExchangeRequest
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	for _, req := range []ExchangeRequest{request, decoded} {
+		signer, err := RecoverSigner(req, false)
+		if err != nil {
+			t.Fatalf("RecoverSigner() error = %v", err)
+		}
+		if signer != api.Signer().Address() {
+			t.Errorf("RecoverSigner() = %s, want %s", signer, api.Signer().Address())
+		}
+		if _, err := RecoverSigner(req, true); !errors.Is(err, ErrNetworkMismatch) {
+			t.Errorf("RecoverSigner(mainnet) error = %v, want ErrNetworkMismatch", err)
+		}
+	}
+}
+
+func TestRecoverSigner_JsonToMsgpack(t *testing.T) {
+	action := OrderWiresToOrderAction([]OrderWire{{
+		Asset:     10001,
+		IsBuy:     true,
+		LimitPx:   "0.5",
+		SizePx:    "300",
+		OrderType: OrderTypeToWire(OrderType{Limit: &LimitOrderType{Tif: TifAlo}}),
+		Cloid:     "0x00000000000000000000000000000001",
+	}}, GroupingNa)
+	data, _ := json.Marshal(action)
+	fromJSON, err := jsonToMsgpack(data)
+	if err != nil {
+		t.Fatalf("jsonToMsgpack() error = %v", err)
+	}
+	hash, _ := buildActionHash(action, "", 1)
+	if got := buildActionHashFromMsgpack(fromJSON, "", 1); got != hash {
+		t.Errorf("hash from JSON = %s, want %s", got, hash)
+	}
+}
@@ -0,0 +1,223 @@
+package hyperliquid
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer signs the EIP-712 digests of exchange actions.
+// The private key doesn't have to live in the process: it can be held in memory (PKeyManager),
+// in an encrypted keystore file (KeystoreSigner) or by an external service (RemoteSigner).
+type Signer interface {
+	// SignDigest signs a 32-byte EIP-712 digest and returns the 65-byte signature [R || S || V] with V in {0, 1}.
+	SignDigest(digest [32]byte) ([]byte, error)
+	// Address returns the address of the signing key.
+	Address() common.Address
+}
+
+// SignTypedData hashes the EIP-712 request, signs it with the signer and returns the signature in VRS format.
+func SignTypedData(signer Signer, request *SignRequest) (byte, [32]byte, [32]byte, error) {
+	if signer == nil {
+		return 0, [32]byte{}, [32]byte{}, APIError{Message: "Signer not set"}
+	}
+	digest, err := request.Digest()
+	if err != nil {
+		return 0, [32]byte{}, [32]byte{}, err
+	}
+	signature, err := signer.SignDigest(digest)
+	if err != nil {
+		return 0, [32]byte{}, [32]byte{}, fmt.Errorf("error signing typed data: %w", err)
+	}
+	if len(signature) != crypto.SignatureLength {
+		return 0, [32]byte{}, [32]byte{}, fmt.Errorf("invalid signature length %d", len(signature))
+	}
+	return SignatureToVRS(signature)
+}
+
+// KeystoreSigner signs with a key stored in an encrypted go-ethereum JSON keystore file.
+// The key is decrypted for each signature and dropped right after, so it never stays in memory.
+// Decryption runs scrypt, which takes up to a second with the standard keystore parameters.
+type KeystoreSigner struct {
+	keyJSON    []byte
+	address    common.Address
+	passphrase func() (string, error)
+}
+
+// NewKeystoreSigner reads a go-ethereum keystore file.
+// The passphrase callback is called every time a digest is signed.
+func NewKeystoreSigner(path string, passphrase func() (string, error)) (*KeystoreSigner, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeystoreSignerFromJSON(keyJSON, passphrase)
+}
+
+// NewKeystoreSignerFromJSON is the same as NewKeystoreSigner but takes the content of the keystore file.
+func NewKeystoreSignerFromJSON(keyJSON []byte, passphrase func() (string, error)) (*KeystoreSigner, error) {
+	var header struct {
+		Address string `json:"address"`
+	}
+	if err := json.Unmarshal(keyJSON, &header); err != nil {
+		return nil, fmt.Errorf("invalid keystore: %w", err)
+	}
+	if !common.IsHexAddress(header.Address) {
+		return nil, fmt.Errorf("invalid keystore address %q", header.Address)
+	}
+	return &KeystoreSigner{
+		keyJSON:    keyJSON,
+		address:    common.HexToAddress(header.Address),
+		passphrase: passphrase,
+	}, nil
+}
+
+func (ks *KeystoreSigner) Address() common.Address {
+	return ks.address
+}
+
+func (ks *KeystoreSigner) SignDigest(digest [32]byte) ([]byte, error) {
+	passphrase, err := ks.passphrase()
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(ks.keyJSON, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key)
+	if key.Address != ks.address {
+		return nil, fmt.Errorf("keystore key %s doesn't match address %s", key.Address, ks.address)
+	}
+	return crypto.Sign(digest[:], key.PrivateKey)
+}
+
+// Helper function to wipe a decrypted keystore key
+func zeroKey(key *keystore.Key) {
+	if key.PrivateKey != nil {
+		zeroBigInt(key.PrivateKey.D)
+	}
+}
+
+// Helper function to wipe a secret big.Int.
+// SetInt64(0) alone only shortens the number, its words would stay in the backing array.
+func zeroBigInt(x *big.Int) {
+	clear(x.Bits())
+	x.SetInt64(0)
+}
+
+// RemoteSigner signs with a key held by an external HTTP service (e.g. a KMS or HSM proxy).
+//
+// The digest is sent as a POST request with the JSON body
+//
+//	{"address": "0x...", "digest": "0x<32 bytes>"}
+//
+// and the service must answer with the JSON body
+//
+//	{"signature": "0x<65 bytes [R || S || V]>"}
+//
+// V can be 0/1 or 27/28. The signature is verified against the address before it is used.
+type RemoteSigner struct {
+	url        string
+	address    common.Address
+	httpClient *http.Client
+	header     http.Header
+}
+
+// NewRemoteSigner creates a signer that sends digests to url for the key of the given address.
+// Requests time out after REMOTE_SIGNER_TIMEOUT, use SetHTTPClient to change it.
+func NewRemoteSigner(url string, address string) (*RemoteSigner, error) {
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("invalid address %q", address)
+	}
+	return &RemoteSigner{
+		url:        url,
+		address:    common.HexToAddress(address),
+		httpClient: &http.Client{Timeout: REMOTE_SIGNER_TIMEOUT},
+		header:     http.Header{},
+	}, nil
+}
+
+// SetHTTPClient sets the HTTP client used to reach the signing service (e.g. with mTLS or a timeout).
+func (rs *RemoteSigner) SetHTTPClient(httpClient *http.Client) {
+	rs.httpClient = httpClient
+}
+
+// SetHeader sets a header sent with every signing request (e.g. Authorization).
+func (rs *RemoteSigner) SetHeader(key, value string) {
+	rs.header.Set(key, value)
+}
+
+func (rs *RemoteSigner) Address() common.Address {
+	return rs.address
+}
+
+func (rs *RemoteSigner) SignDigest(digest [32]byte) ([]byte, error) {
+	payload, err := json.Marshal(map[string]string{
+		"address": rs.address.Hex(),
+		"digest":  hexutil.Encode(digest[:]),
+	})
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequest("POST", rs.url, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range rs.header {
+		request.Header[key] = values
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := rs.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode >= http.StatusBadRequest {
+		return nil, APIError{Message: fmt.Sprintf("Remote signer HTTP %d: %s", response.StatusCode, data)}
+	}
+	var result struct {
+		Signature string `json:"signature"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("invalid remote signer response: %w", err)
+	}
+	signature, recovered, err := parseSignature(result.Signature, digest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote signature: %w", err)
+	}
+	if recovered != rs.address {
+		return nil, fmt.Errorf("remote signature is from %s, expected %s", recovered, rs.address)
+	}
+	return signature, nil
+}
+
+// parseSignature decodes a 65-byte hex signature of the digest, as returned by wallets (V in 27/28) or signers (V in 0/1).
+// It returns the signature with V in 0/1 and the address that signed it.
+func parseSignature(signatureHex string, digest [32]byte) ([]byte, common.Address, error) {
+	signature, err := hexutil.Decode(signatureHex)
+	if err != nil || len(signature) != crypto.SignatureLength {
+		return nil, common.Address{}, fmt.Errorf("invalid signature %q", signatureHex)
+	}
+	if signature[64] >= 27 {
+		signature[64] -= 27
+	}
+	publicKey, err := crypto.SigToPub(digest[:], signature)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	return signature, crypto.PubkeyToAddress(*publicKey), nil
+}
@@ -0,0 +1,150 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+const testPrivateKey = "0123456789012345678901234567890123456789012345678901234567890123"
+
+func getTestSignRequest() *SignRequest {
+	hash := crypto.Keccak256Hash([]byte("action"))
+	return &SignRequest{
+		DomainName:  "Exchange",
+		PrimaryType: "Agent",
+		DType: []apitypes.Type{
+			{Name: "source", Type: "string"},
+			{Name: "connectionId", Type: "bytes32"},
+		},
+		DTypeMsg:  buildMessage(hash.Bytes(), false),
+		IsMainNet: false,
+	}
+}
+
+// Checks that the signature of the request recovers to the address of the key
+func checkSigner(t *testing.T, signer Signer, expected *PKeyManager) {
+	request := getTestSignRequest()
+	v, r, s, err := SignTypedData(signer, request)
+	if err != nil {
+		t.Fatalf("SignTypedData() error = %v", err)
+	}
+	wantV, wantR, wantS, _ := SignTypedData(expected, request)
+	if v != wantV || r != wantR || s != wantS {
+		t.Errorf("SignTypedData() = %v %x %x, want %v %x %x", v, r, s, wantV, wantR, wantS)
+	}
+	if signer.Address() != expected.PublicAddress() {
+		t.Errorf("Address() = %s, want %s", signer.Address(), expected.PublicAddress())
+	}
+}
+
+func TestSigner_PKeyManager(t *testing.T) {
+	km, err := NewPKeyManager(testPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := getTestSignRequest()
+	v, r, s, err := SignTypedData(km, request)
+	if err != nil {
+		t.Fatalf("SignTypedData() error = %v", err)
+	}
+	hash, _, _ := apitypes.TypedDataAndHash(SignRequestToEIP712TypedData(request))
+	signature := append(append(r[:], s[:]...), v-27)
+	publicKey, err := crypto.SigToPub(hash, signature)
+	if err != nil {
+		t.Fatalf("SigToPub() error = %v", err)
+	}
+	if crypto.PubkeyToAddress(*publicKey) != km.PublicAddress() {
+		t.Errorf("recovered address = %s, want %s", crypto.PubkeyToAddress(*publicKey), km.PublicAddress())
+	}
+	if _, _, _, err := SignTypedData(nil, request); err == nil {
+		t.Errorf("SignTypedData(nil) error = nil, want error")
+	}
+}
+
+func TestSigner_Keystore(t *testing.T) {
+	km, _ := NewPKeyManager(testPrivateKey)
+	keyJSON, err := keystore.EncryptKey(&keystore.Key{
+		Address:    km.PublicAddress(),
+		PrivateKey: km.privateKey,
+	}, "secret", keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := NewKeystoreSignerFromJSON(keyJSON, func() (string, error) { return "secret", nil })
+	if err != nil {
+		t.Fatalf("NewKeystoreSignerFromJSON() error = %v", err)
+	}
+	checkSigner(t, signer, km)
+
+	wrong, _ := NewKeystoreSignerFromJSON(keyJSON, func() (string, error) { return "wrong", nil })
+	if _, err := wrong.SignDigest([32]byte{}); err == nil {
+		t.Errorf("SignDigest() with wrong passphrase error = nil, want error")
+	}
+
+	// The decrypted key must be wiped in memory, not only set to 0
+	key, err := keystore.DecryptKey(keyJSON, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	words := key.PrivateKey.D.Bits()
+	zeroKey(key)
+	for i, word := range words {
+		if word != 0 {
+			t.Errorf("word %d of the key = %x after zeroKey(), want 0", i, word)
+		}
+	}
+}
+
+func TestSigner_Remote(t *testing.T) {
+	km, _ := NewPKeyManager(testPrivateKey)
+	other, _ := NewPKeyManager("1123456789012345678901234567890123456789012345678901234567890123")
+	signWith := km
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var request struct {
+			Address string `json:"address"`
+			Digest  string `json:"digest"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("Decode() error = %v", err)
+		}
+		if request.Address != km.PublicAddressHex() {
+			t.Errorf("address = %s, want %s", request.Address, km.PublicAddressHex())
+		}
+		var digest [32]byte
+		copy(digest[:], hexutil.MustDecode(request.Digest))
+		signature, _ := signWith.SignDigest(digest)
+		signature[64] += 27 // services often return V as 27/28
+		json.NewEncoder(w).Encode(map[string]string{"signature": hexutil.Encode(signature)})
+	}))
+	defer server.Close()
+
+	signer, err := NewRemoteSigner(server.URL, km.PublicAddressHex())
+	if err != nil {
+		t.Fatalf("NewRemoteSigner() error = %v", err)
+	}
+	if signer.httpClient.Timeout != REMOTE_SIGNER_TIMEOUT {
+		t.Errorf("NewRemoteSigner() timeout = %s, want %s", signer.httpClient.Timeout, REMOTE_SIGNER_TIMEOUT)
+	}
+	if _, err := signer.SignDigest([32]byte{}); err == nil {
+		t.Errorf("SignDigest() without authorization error = nil, want error")
+	}
+	signer.SetHeader("Authorization", "Bearer token")
+	checkSigner(t, signer, km)
+
+	// A signature from another key must be rejected
+	signWith = other
+	if _, err := signer.SignDigest([32]byte{1}); err == nil {
+		t.Errorf("SignDigest() with wrong key error = nil, want error")
+	}
+}
@@ -0,0 +1,98 @@
+package hyperliquid
+
+import "math"
+
+// Summary statistics of a portfolio history window
+type PortfolioStats struct {
+	Periods        int     // Number of returns the statistics are computed from
+	TotalReturn    float64 // Compounded return over the window
+	MeanReturn     float64 // Mean return per period
+	StdDev         float64 // Standard deviation of the returns per period
+	Sharpe         float64 // Annualised mean/stddev ratio, risk free rate is 0
+	MaxDrawdown    float64 // Largest peak to trough decline of the compounded returns (0.1 = 10%)
+	MaxDrawdownPnl float64 // Largest peak to trough decline of the cumulative PnL in USD
+}
+
+// Values returns the values of the series without timestamps
+func (ts TimeSeries) Values() []float64 {
+	values := make([]float64, len(ts))
+	for i, point := range ts {
+		values[i] = point.Value
+	}
+	return values
+}
+
+// MaxDrawdown returns the largest peak to trough decline of the series in absolute terms
+func (ts TimeSeries) MaxDrawdown() float64 {
+	if len(ts) == 0 {
+		return 0
+	}
+	peak := ts[0].Value
+	maxDrawdown := 0.0
+	for _, point := range ts {
+		peak = math.Max(peak, point.Value)
+		maxDrawdown = math.Max(maxDrawdown, peak-point.Value)
+	}
+	return maxDrawdown
+}
+
+// Returns calculates the return of each period of the history.
+// A period return is the PnL change divided by the account value at the start of the period,
+// so deposits and withdrawals don't show up as gains or losses.
+// Periods starting with a non-positive account value are skipped.
+func (h *PortfolioHistory) Returns() []float64 {
+	accountValues := make(map[int64]float64, len(h.AccountValueHistory))
+	for _, point := range h.AccountValueHistory {
+		accountValues[point.Time] = point.Value
+	}
+	var returns []float64
+	for i := 1; i < len(h.PnlHistory); i++ {
+		prev := h.PnlHistory[i-1]
+		accountValue, ok := accountValues[prev.Time]
+		if !ok || accountValue <= 0 {
+			continue
+		}
+		returns = append(returns, (h.PnlHistory[i].Value-prev.Value)/accountValue)
+	}
+	return returns
+}
+
+// Stats calculates return, volatility, Sharpe-style ratio and drawdowns of the history.
+// The Sharpe ratio is annualised using the average spacing of the PnL history points.
+func (h *PortfolioHistory) Stats() PortfolioStats {
+	returns := h.Returns()
+	stats := PortfolioStats{
+		Periods:        len(returns),
+		MaxDrawdownPnl: h.PnlHistory.MaxDrawdown(),
+	}
+	if len(returns) == 0 {
+		return stats
+	}
+
+	equity, peak := 1.0, 1.0
+	sum := 0.0
+	for _, r := range returns {
+		sum += r
+		equity *= 1 + r
+		peak = math.Max(peak, equity)
+		stats.MaxDrawdown = math.Max(stats.MaxDrawdown, (peak-equity)/peak)
+	}
+	stats.TotalReturn = equity - 1
+	stats.MeanReturn = sum / float64(len(returns))
+
+	if len(returns) < 2 {
+		return stats
+	}
+	variance := 0.0
+	for _, r := range returns {
+		variance += (r - stats.MeanReturn) * (r - stats.MeanReturn)
+	}
+	stats.StdDev = math.Sqrt(variance / float64(len(returns)-1))
+
+	span := h.PnlHistory[len(h.PnlHistory)-1].Time - h.PnlHistory[0].Time
+	if stats.StdDev > 0 && span > 0 {
+		periodMs := float64(span) / float64(len(h.PnlHistory)-1)
+		stats.Sharpe = stats.MeanReturn / stats.StdDev * math.Sqrt(MILLISECONDS_PER_YEAR/periodMs)
+	}
+	return stats
+}
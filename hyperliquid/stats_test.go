@@ -0,0 +1,53 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestStats_PortfolioHistory(t *testing.T) {
+	// Account starts at 1000, +100, -220 after a 100 deposit, +55
+	data := `{"accountValueHistory":[[0,"1000.0"],[3600000,"1100.0"],[7200000,"980.0"],[10800000,"1035.0"]],
+		"pnlHistory":[[0,"0.0"],[3600000,"100.0"],[7200000,"-120.0"],[10800000,"-65.0"]],"vlm":"12345.6"}`
+	var history PortfolioHistory
+	if err := json.Unmarshal([]byte(data), &history); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	returns := history.Returns()
+	expected := []float64{0.1, -0.2, 55.0 / 980.0}
+	if len(returns) != len(expected) {
+		t.Fatalf("Returns() = %v, want %v", returns, expected)
+	}
+	for i := range expected {
+		if math.Abs(returns[i]-expected[i]) > 1e-12 {
+			t.Errorf("Returns()[%d] = %v, want %v", i, returns[i], expected[i])
+		}
+	}
+
+	stats := history.Stats()
+	if stats.Periods != 3 {
+		t.Errorf("Stats().Periods = %v, want %v", stats.Periods, 3)
+	}
+	totalReturn := 1.1*0.8*(1+55.0/980.0) - 1
+	if math.Abs(stats.TotalReturn-totalReturn) > 1e-12 {
+		t.Errorf("Stats().TotalReturn = %v, want %v", stats.TotalReturn, totalReturn)
+	}
+	if math.Abs(stats.MaxDrawdown-0.2) > 1e-12 {
+		t.Errorf("Stats().MaxDrawdown = %v, want %v", stats.MaxDrawdown, 0.2)
+	}
+	if stats.MaxDrawdownPnl != 220 {
+		t.Errorf("Stats().MaxDrawdownPnl = %v, want %v", stats.MaxDrawdownPnl, 220)
+	}
+	if stats.StdDev <= 0 || stats.Sharpe == 0 {
+		t.Errorf("Stats() = %+v, want non zero StdDev and Sharpe", stats)
+	}
+}
+
+func TestStats_EmptyHistory(t *testing.T) {
+	history := PortfolioHistory{}
+	stats := history.Stats()
+	if stats != (PortfolioStats{}) {
+		t.Errorf("Stats() = %+v, want zero value", stats)
+	}
+}
@@ -15,6 +15,7 @@ var nonceCounter = time.Now().UnixMilli()
 // Hyperliquid uses timestamps in milliseconds for nonce
 // GetNonce returns a unique nonce that is always at least the current time in milliseconds.
 // It ensures thread-safe updates using atomic operations.
+// The counter is shared by all signers of the process. ExchangeAPI uses the NonceManager of the client instead.
 func GetNonce() uint64 {
 	now := time.Now().UnixMilli()
 	for {
@@ -93,3 +94,13 @@ func GetDefaultTimeRange() (int64, int64) {
 	startTime := time.Now().AddDate(0, 0, -90).UnixMilli()
 	return startTime, endTime
 }
+
+// Convert a funding rate paid every intervalHours to an annualised rate
+//
+//	AnnualizeFundingRate(0.0000125, 1) // 0.1095 (10.95% APR)
+func AnnualizeFundingRate(rate float64, intervalHours int) float64 {
+	if intervalHours <= 0 {
+		return 0
+	}
+	return rate * 24 * 365 / float64(intervalHours)
+}